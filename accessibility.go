@@ -0,0 +1,183 @@
+package main
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// AccessibilityConfig: NoColor drops all colors (also enabled by a
+// non-empty NO_COLOR, see no-color.org), HighContrast swaps the palette
+// for bright white on black with bold state colors, and TextLabels spells
+// out indicators that would otherwise be a lone icon.
+type AccessibilityConfig struct {
+	NoColor      bool `json:"no_color"`
+	HighContrast bool `json:"high_contrast"`
+	TextLabels   bool `json:"text_labels"`
+}
+
+var (
+	textLabels    bool
+	accessibility AccessibilityConfig
+)
+
+var iconLabels = map[string]string{
+	"󰀝": "airplane",
+	"󰀞": "radios",
+	"󰖩": "wifi",
+	"󰖪": "wifi off",
+	"󰤨": "wifi excellent",
+	"󰤥": "wifi good",
+	"󰤢": "wifi fair",
+	"󰤟": "wifi weak",
+	"󰈀": "wired",
+	"󰖟": "portal",
+	"󰇖": "no dns",
+	"󰤫": "no internet",
+	"󰂯": "bt",
+	"󰂲": "bt off",
+	"󰄀": "cam",
+	"󰗟": "cam off",
+	"󰍬": "mic",
+	"󰍭": "mic off",
+	"󰖙": "night off",
+	"󰖔": "night",
+	"󰌾": "lock",
+	"󰈈": "eyes",
+	"󱎫": "time",
+	"󰔟": "countdown",
+	"󰥔": "next",
+	"󰓾": "focus",
+	"󰌘": "tether",
+	"󰀂": "hotspot",
+	"󰅶": "awake",
+	"󰾪": "awake off",
+	"󰕾": "vol",
+	"󰖀": "vol",
+	"󰕿": "vol",
+	"󰝟": "muted",
+	"󰕥": "capped",
+	"󰢮": "gpu",
+	"󰒃": "firewall",
+	"󰦞": "firewall off",
+	"󰒙": "failed logins",
+	"󰁯": "backup",
+	"󰐪": "print",
+	"󰕓": "usb",
+	"󰌌": "keyboard",
+	"󰄜": "phone",
+	"": "special",
+	"󰋋": "headset",
+	"󰍹": "sessions",
+	"󰏖": "flatpak",
+	"󰏗": "snap",
+	"󰆧": "container",
+	"󰡨": "docker",
+	"󰐊": "playing",
+	"󰏤": "paused",
+	"󰓛": "stopped",
+	"󰅛": "stale",
+	"󰀦": "error",
+	"󰂄": "charging",
+	"󰚥": "plugged in",
+	"󰁹": "bat",
+	"󰂂": "bat",
+	"󰂁": "bat",
+	"󰂀": "bat",
+	"󰁿": "bat",
+	"󰁾": "bat",
+	"󰁽": "bat",
+	"󰁼": "bat",
+	"󰁻": "bat low",
+	"󰁺": "bat empty",
+	"󰏪": "stylus",
+	"󰊴": "gamepad",
+	"󰕴": "dwindle",
+	"󰘸": "master",
+	"󰕰": "tiles",
+	"󰕮": "layout",
+	"󰻠": "cpu",
+	"󰍛": "mem",
+	"󰋊": "disk",
+	"󰒋": "service",
+	"󰔛": "frame",
+	"󰂚": "notifications",
+	"󰓃": "ring",
+	"󰅇": "clipboard",
+	"󰅖": "close",
+	"󰕔": "eject",
+	"󰉈": "float",
+	"󰐄": "unpin",
+	"󰐃": "pin",
+	"󰸉": "wallpaper",
+	"󰍉": "search",
+	"󰕍": "history",
+	"󰆍": "terminal",
+	"󰐱": "plugin",
+	"󰅩": "script",
+	"󰈹": "firefox",
+	"󰊯": "chrome",
+	"󰞷": "terminal",
+	"󰨞": "code",
+	"󰙯": "discord",
+	"󰓇": "spotify",
+	"󰉋": "files",
+	"󱓧": "notes",
+	"󰓓": "steam",
+}
+
+// icon returns glyph, or its text label when text labels are enabled.
+func icon(glyph string) string {
+	if !textLabels {
+		return glyph
+	}
+	if label, ok := iconLabels[glyph]; ok {
+		return label
+	}
+	return glyph
+}
+
+func applyAccessibility(config AccessibilityConfig) {
+	accessibility = config
+	textLabels = config.TextLabels
+
+	if config.HighContrast {
+		applyHighContrast()
+	}
+	if config.NoColor || os.Getenv("NO_COLOR") != "" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		// without color, state has to show through attributes
+		workspaceActiveStyle = workspaceActiveStyle.Reverse(true)
+		warningStyle = warningStyle.Underline(true)
+		criticalStyle = criticalStyle.Bold(true).Reverse(true)
+		popupStyle = popupStyle.Reverse(true)
+		popupHighlightStyle = popupHighlightStyle.Underline(true)
+	}
+}
+
+func applyHighContrast() {
+	fg, bg := lipgloss.Color("15"), lipgloss.Color("0")
+	for _, s := range []*lipgloss.Style{
+		&boxStyle, &activeBoxStyle, &workspaceStyle, &cpuStyle, &memoryStyle,
+		&diskStyle, &batteryStyle, &networkStyle, &clockStyle, &layoutStyle,
+		&windowTitleStyle, &staleStyle, &dimStyle, &monitorLabelStyle, &monitorLabelActiveStyle,
+	} {
+		*s = s.Foreground(fg).BorderForeground(fg)
+	}
+	for s, color := range map[*lipgloss.Style]lipgloss.Color{
+		&warningStyle:         "11",
+		&criticalStyle:        "9",
+		&goodStyle:            "10",
+		&batteryChargingStyle: "10",
+		&batteryLowStyle:      "9",
+	} {
+		*s = s.Foreground(color).BorderForeground(color).Bold(true)
+	}
+	activeBoxStyle = activeBoxStyle.Bold(true)
+	workspaceActiveStyle = workspaceActiveStyle.Foreground(bg).Background(fg).Bold(true)
+	popupStyle = popupStyle.Foreground(fg).Background(bg)
+	popupHighlightStyle = popupHighlightStyle.Foreground(lipgloss.Color("11")).Background(bg)
+	hiddenLineStyle = hiddenLineStyle.Foreground(fg)
+	tooltipStyle = tooltipStyle.Foreground(fg)
+}
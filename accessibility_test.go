@@ -0,0 +1,56 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// isGlyph reports whether r is in a private use area, where Nerd Font
+// puts its icons.
+func isGlyph(r rune) bool {
+	return unicode.In(r, unicode.Co)
+}
+
+// TestIconLabels checks every string constant that carries a glyph: it has
+// to be a lone glyph with an entry in iconLabels, so text-label mode never
+// shows a raw Nerd Font glyph and glyphs aren't baked into longer strings
+// where icon() can't swap them.
+func TestIconLabels(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			lit, ok := n.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			s, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.ContainsFunc(s, isGlyph) {
+				return true
+			}
+			if _, ok := iconLabels[s]; !ok {
+				t.Errorf("%s: %q is not a glyph in iconLabels", fset.Position(lit.Pos()), s)
+			}
+			return true
+		})
+	}
+}
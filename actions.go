@@ -0,0 +1,63 @@
+package main
+
+import (
+	"log"
+	"os/exec"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ClickActions are shell commands run when a module is clicked or
+// scrolled, keyed by module id in Config.Actions, e.g.
+//
+//	"clock": {"on_click": "gnome-calendar"}
+//
+// A command replaces the module's own handling of that button only.
+type ClickActions struct {
+	OnClick       string `json:"on_click"`
+	OnRightClick  string `json:"on_right_click"`
+	OnMiddleClick string `json:"on_middle_click"`
+	OnScrollUp    string `json:"on_scroll_up"`
+	OnScrollDown  string `json:"on_scroll_down"`
+}
+
+func (a ClickActions) command(button tea.MouseEventType) string {
+	switch button {
+	case tea.MouseLeft:
+		return a.OnClick
+	case tea.MouseRight:
+		return a.OnRightClick
+	case tea.MouseMiddle:
+		return a.OnMiddleClick
+	case tea.MouseWheelUp:
+		return a.OnScrollUp
+	case tea.MouseWheelDown:
+		return a.OnScrollDown
+	}
+	return ""
+}
+
+// clickAction runs the configured command for a click on module id, if
+// there is one.
+func (m model) clickAction(id string, msg tea.MouseMsg) (tea.Cmd, bool) {
+	command := m.config.Actions[id].command(msg.Type)
+	if command == "" {
+		return nil, false
+	}
+	return func() tea.Msg {
+		runDetached(id, command)
+		return nil
+	}, true
+}
+
+// runDetached starts command in its own session so it outlives the bar.
+func runDetached(id, command string) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		log.Printf("%s: failed to run %q: %v", id, command, err)
+		return
+	}
+	go cmd.Wait()
+}
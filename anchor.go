@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func barAlign(align string) lipgloss.Position {
+	switch align {
+	case "center":
+		return lipgloss.Center
+	case "bottom":
+		return lipgloss.Bottom
+	}
+	return lipgloss.Top
+}
+
+func (m model) bottomAnchored() bool {
+	return m.config.Bar.Anchor == "bottom" && !m.config.Inline
+}
+
+// frame is the laid-out screen: the bar, the tooltip line if reserved, and
+// an open popup, which sit below a top bar or above a bottom one when the
+// terminal has room.
+type frame struct {
+	bar        string
+	zones      []hitZone
+	barTop     int
+	tooltip    string
+	hasTooltip bool
+	popup      []string
+	popupZones [][]hitZone
+	popupX     int
+	popupTop   int
+	bottom     bool
+}
+
+func (m model) layoutFrame() frame {
+	bar, zones := m.renderBar()
+	f := frame{bar: bar, zones: zones, bottom: m.bottomAnchored()}
+	height := lipgloss.Height(bar)
+	f.tooltip, f.hasTooltip = m.tooltipLine(height)
+	reserved := height
+	if f.hasTooltip {
+		reserved++
+	}
+
+	f.popup, f.popupZones, f.popupX = m.renderPopup(zones)
+	if room := max(m.height-reserved, 0); len(f.popup) > room {
+		f.popup, f.popupZones = f.popup[:room], f.popupZones[:room]
+	}
+
+	if f.bottom {
+		f.barTop = max(m.height-height, 0)
+		f.popupTop = max(m.height-reserved, 0) - len(f.popup)
+	} else {
+		f.popupTop = reserved
+	}
+	return f
+}
+
+func (f frame) render() string {
+	var near []string
+	if f.hasTooltip {
+		near = append(near, f.tooltip)
+	}
+	if !f.bottom {
+		lines := append([]string{f.bar}, near...)
+		return strings.Join(append(lines, f.popup...), "\n")
+	}
+	lines := make([]string, f.popupTop, f.popupTop+len(f.popup)+len(near)+1)
+	lines = append(lines, f.popup...)
+	lines = append(lines, near...)
+	return strings.Join(append(lines, f.bar), "\n")
+}
+
+// onBar reports whether terminal row y falls on the rendered bar.
+func (f frame) onBar(y int) bool {
+	return y >= f.barTop && y < f.barTop+lipgloss.Height(f.bar)
+}
+
+// popupAt returns the popup hit target under x, y, and whether that
+// position is inside the popup at all.
+func (f frame) popupAt(x, y int) (string, bool) {
+	row := y - f.popupTop
+	if row < 0 || row >= len(f.popup) {
+		return "", false
+	}
+	return zoneAt(f.popupZones[row], x-f.popupX), true
+}
+
+// anchor places content that has no popup, such as the hidden bar line.
+func (m model) anchor(content string) string {
+	if !m.bottomAnchored() {
+		return content
+	}
+	return strings.Repeat("\n", max(m.height-lipgloss.Height(content), 0)) + content
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// freedesktop appearance portal: color-scheme is 0 (no preference),
+// 1 (prefer dark) or 2 (prefer light)
+const (
+	portalService    = "org.freedesktop.portal.Desktop"
+	portalPath       = "/org/freedesktop/portal/desktop"
+	portalSettings   = "org.freedesktop.portal.Settings"
+	appearanceNS     = "org.freedesktop.appearance"
+	colorSchemeKey   = "color-scheme"
+	colorSchemeDark  = 1
+	colorSchemeLight = 2
+)
+
+// AppearanceWatcher follows the desktop's dark/light preference through
+// the portal's SettingChanged signal.
+type AppearanceWatcher struct {
+	mu       sync.RWMutex
+	scheme   uint32
+	conn     *dbus.Conn
+	stopOnce sync.Once
+}
+
+func newAppearanceWatcher() *AppearanceWatcher {
+	return &AppearanceWatcher{}
+}
+
+func (w *AppearanceWatcher) Start() error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+
+	var v dbus.Variant
+	if err := conn.Object(portalService, portalPath).Call(portalSettings+".Read", 0, appearanceNS, colorSchemeKey).Store(&v); err == nil {
+		w.set(v)
+	}
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchInterface(portalSettings),
+		dbus.WithMatchMember("SettingChanged"),
+		dbus.WithMatchArg(0, appearanceNS),
+	); err != nil {
+		return err
+	}
+	signals := make(chan *dbus.Signal, 8)
+	conn.Signal(signals)
+	go func() {
+		for sig := range signals {
+			if len(sig.Body) < 3 || sig.Body[1] != colorSchemeKey {
+				continue
+			}
+			if v, ok := sig.Body[2].(dbus.Variant); ok {
+				w.set(v)
+			}
+		}
+	}()
+	return nil
+}
+
+func (w *AppearanceWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		if w.conn != nil {
+			w.conn.Close()
+		}
+	})
+}
+
+// set unwraps the value; the older Read API nests it in a second variant.
+func (w *AppearanceWatcher) set(v dbus.Variant) {
+	value := v.Value()
+	for {
+		inner, ok := value.(dbus.Variant)
+		if !ok {
+			break
+		}
+		value = inner.Value()
+	}
+	scheme, ok := value.(uint32)
+	if !ok {
+		return
+	}
+	w.mu.Lock()
+	w.scheme = scheme
+	w.mu.Unlock()
+}
+
+// Scheme is the current color-scheme; a nil watcher has no preference.
+func (w *AppearanceWatcher) Scheme() uint32 {
+	if w == nil {
+		return 0
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.scheme
+}
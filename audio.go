@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// audio control through pactl, which talks to both PulseAudio and
+// PipeWire's pulse server
+
+type audioStream struct {
+	id     int
+	app    string
+	volume int
+	muted  bool
+}
+
+type audioState struct {
+	volume  int
+	muted   bool
+	streams []audioStream
+	// set when the default sink is a bluetooth headset reporting its battery
+	headset *headsetBattery
+}
+
+func pactl(args ...string) (string, error) {
+	out, err := exec.Command("pactl", args...).Output()
+	return string(out), err
+}
+
+// parsePercent picks the first "NN%" out of pactl's volume output, e.g.
+// "Volume: front-left: 32768 /  50% / -18.06 dB, ..."
+func parsePercent(s string) int {
+	for _, field := range strings.Fields(s) {
+		if p, ok := strings.CutSuffix(field, "%"); ok {
+			if v, err := strconv.Atoi(p); err == nil {
+				return v
+			}
+		}
+	}
+	return 0
+}
+
+func fetchAudioState(withStreams bool) (audioState, error) {
+	var state audioState
+	out, err := pactl("get-sink-volume", "@DEFAULT_SINK@")
+	if err != nil {
+		return state, err
+	}
+	state.volume = parsePercent(out)
+
+	out, err = pactl("get-sink-mute", "@DEFAULT_SINK@")
+	if err != nil {
+		return state, err
+	}
+	state.muted = strings.Contains(out, "yes")
+
+	if sink, err := pactl("get-default-sink"); err == nil {
+		if addr := bluezAddress(strings.TrimSpace(sink)); addr != "" {
+			if battery, ok := fetchHeadsetBattery(addr); ok {
+				state.headset = &battery
+			}
+		}
+	}
+
+	if withStreams {
+		state.streams, err = fetchAudioStreams()
+	}
+	return state, err
+}
+
+func fetchAudioStreams() ([]audioStream, error) {
+	out, err := pactl("-f", "json", "list", "sink-inputs")
+	if err != nil {
+		return nil, err
+	}
+
+	var inputs []struct {
+		Index  int  `json:"index"`
+		Mute   bool `json:"mute"`
+		Volume map[string]struct {
+			ValuePercent string `json:"value_percent"`
+		} `json:"volume"`
+		Properties map[string]string `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(out), &inputs); err != nil {
+		return nil, err
+	}
+
+	streams := make([]audioStream, 0, len(inputs))
+	for _, in := range inputs {
+		s := audioStream{id: in.Index, muted: in.Mute}
+		for _, v := range in.Volume {
+			s.volume = max(s.volume, parsePercent(v.ValuePercent))
+		}
+		s.app = in.Properties["application.name"]
+		if s.app == "" {
+			s.app = in.Properties["media.name"]
+		}
+		streams = append(streams, s)
+	}
+	sort.Slice(streams, func(i, j int) bool { return streams[i].id < streams[j].id })
+	return streams, nil
+}
+
+func adjustSinkVolume(delta int) error {
+	_, err := pactl("set-sink-volume", "@DEFAULT_SINK@", fmt.Sprintf("%+d%%", delta))
+	return err
+}
+
+func toggleSinkMute() error {
+	_, err := pactl("set-sink-mute", "@DEFAULT_SINK@", "toggle")
+	return err
+}
+
+func adjustStreamVolume(id, delta int) error {
+	_, err := pactl("set-sink-input-volume", strconv.Itoa(id), fmt.Sprintf("%+d%%", delta))
+	return err
+}
+
+func toggleStreamMute(id int) error {
+	_, err := pactl("set-sink-input-mute", strconv.Itoa(id), "toggle")
+	return err
+}
+
+func volumeBar(volume, width int) string {
+	filled := min(max(volume*width/100, 0), width)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+func volumeIcon(volume int, muted bool) string {
+	switch {
+	case muted:
+		return icon("󰝟")
+	case volume >= 60:
+		return icon("󰕾")
+	case volume >= 25:
+		return icon("󰖀")
+	}
+	return icon("󰕿")
+}
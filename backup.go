@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BackupSource is one place to learn when a backup last succeeded; set
+// exactly one of
+//
+//	"file":      a file a backup script writes a timestamp into (RFC 3339 or
+//	             Unix seconds) or just touches
+//	"unit":      a systemd service's last successful run, system or user
+//	"restic":    the newest snapshot of a repository; RESTIC_PASSWORD_FILE
+//	             and friends come from the environment
+//	"borg":      the newest archive, BORG_PASSCOMMAND from the environment
+//	"timeshift": the newest snapshot under a timeshift directory
+type BackupSource struct {
+	Name      string `json:"name"`
+	File      string `json:"file"`
+	Unit      string `json:"unit"`
+	Restic    string `json:"restic"`
+	Borg      string `json:"borg"`
+	Timeshift string `json:"timeshift"`
+}
+
+func (s BackupSource) label() string {
+	for _, name := range []string{s.Name, s.Unit, filepath.Base(s.File), filepath.Base(s.Restic), filepath.Base(s.Borg)} {
+		if name != "" && name != "." {
+			return name
+		}
+	}
+	return "timeshift"
+}
+
+// lastBackup returns when the source last completed a backup.
+func (s BackupSource) lastBackup() (time.Time, error) {
+	switch {
+	case s.File != "":
+		return fileBackupTime(expandHome(s.File))
+	case s.Unit != "":
+		return unitBackupTime(s.Unit)
+	case s.Restic != "":
+		return resticBackupTime(expandHome(s.Restic))
+	case s.Borg != "":
+		return borgBackupTime(expandHome(s.Borg))
+	case s.Timeshift != "":
+		return timeshiftBackupTime(s.Timeshift)
+	}
+	return time.Time{}, errors.New("no source set")
+}
+
+func fileBackupTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	data, _ := os.ReadFile(path)
+	content := strings.TrimSpace(string(data))
+	if t, err := time.Parse(time.RFC3339, content); err == nil {
+		return t, nil
+	}
+	if secs, err := strconv.ParseInt(content, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	return info.ModTime(), nil
+}
+
+// unitBackupTime is when the service last exited, if that run succeeded.
+func unitBackupTime(unit string) (time.Time, error) {
+	for _, scope := range [][]string{nil, {"--user"}} {
+		args := append(scope, "show", unit, "--timestamp=unix", "--property=LoadState,Result,ExecMainExitTimestamp")
+		out, err := exec.Command("systemctl", args...).Output()
+		if err != nil {
+			continue
+		}
+		props := map[string]string{}
+		for _, line := range strings.Split(string(out), "\n") {
+			if key, value, ok := strings.Cut(line, "="); ok {
+				props[key] = value
+			}
+		}
+		if props["LoadState"] == "not-found" {
+			continue
+		}
+		if props["Result"] != "success" {
+			return time.Time{}, fmt.Errorf("last run: %s", props["Result"])
+		}
+		secs, err := strconv.ParseInt(strings.TrimPrefix(props["ExecMainExitTimestamp"], "@"), 10, 64)
+		if err != nil || secs == 0 {
+			return time.Time{}, errors.New("never ran")
+		}
+		return time.Unix(secs, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("unit %s not found", unit)
+}
+
+func resticBackupTime(repo string) (time.Time, error) {
+	out, err := exec.Command("restic", "--repo", repo, "--no-lock", "snapshots", "--latest", "1", "--json").Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+	var snapshots []struct {
+		Time time.Time `json:"time"`
+	}
+	if err := json.Unmarshal(out, &snapshots); err != nil {
+		return time.Time{}, err
+	}
+	var latest time.Time
+	for _, s := range snapshots {
+		if s.Time.After(latest) {
+			latest = s.Time
+		}
+	}
+	if latest.IsZero() {
+		return latest, errors.New("no snapshots")
+	}
+	return latest, nil
+}
+
+func borgBackupTime(repo string) (time.Time, error) {
+	out, err := exec.Command("borg", "list", "--last", "1", "--json", repo).Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+	var list struct {
+		Archives []struct {
+			Time string `json:"time"`
+		} `json:"archives"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return time.Time{}, err
+	}
+	if len(list.Archives) == 0 {
+		return time.Time{}, errors.New("no archives")
+	}
+	// local time without a zone
+	return time.ParseInLocation("2006-01-02T15:04:05.000000", list.Archives[0].Time, time.Local)
+}
+
+// timeshiftBackupTime reads snapshot directory names, which are their
+// creation time: <dir>/timeshift/snapshots/2024-05-01_10-00-01.
+func timeshiftBackupTime(dir string) (time.Time, error) {
+	var latest time.Time
+	for _, snapshots := range []string{dir, filepath.Join(dir, "snapshots"), filepath.Join(dir, "timeshift", "snapshots")} {
+		entries, _ := os.ReadDir(snapshots)
+		for _, e := range entries {
+			if t, err := time.ParseInLocation("2006-01-02_15-04-05", e.Name(), time.Local); err == nil && t.After(latest) {
+				latest = t
+			}
+		}
+	}
+	if latest.IsZero() {
+		return latest, errors.New("no snapshots")
+	}
+	return latest, nil
+}
@@ -2,36 +2,74 @@ package main
 
 func getBatteryIcon(level int, state string) string {
 	if state == "charging" {
-		return "󰂄"
+		return icon("󰂄")
+	}
+	if state == "idle" {
+		return icon("󰚥")
 	}
 
 	switch {
 	case level >= 90:
-		return "󰁹" // Full
+		return icon("󰁹") // Full
 	case level >= 80:
-		return "󰂂"
+		return icon("󰂂")
 	case level >= 70:
-		return "󰂁"
+		return icon("󰂁")
 	case level >= 60:
-		return "󰂀"
+		return icon("󰂀")
 	case level >= 50:
-		return "󰁿"
+		return icon("󰁿")
 	case level >= 40:
-		return "󰁾"
+		return icon("󰁾")
 	case level >= 30:
-		return "󰁽"
+		return icon("󰁽")
 	case level >= 20:
-		return "󰁼"
+		return icon("󰁼")
 	case level >= 10:
-		return "󰁻"
+		return icon("󰁻")
 	default:
-		return "󰁺" // Critical
+		return icon("󰁺") // Critical
+	}
+}
+
+func getLayoutIcon(layout string) string {
+	switch layout {
+	case "dwindle":
+		return icon("󰕴")
+	case "master":
+		return icon("󰘸")
+	case "hy3", "scroller":
+		return icon("󰕰")
 	}
+	return icon("󰕮")
 }
 
-func getNetworkIcon(state string) string {
-	if state == "connected" {
-		return "󰖩 "
+// getNetworkIcon also shows when a connection is up but the internet
+// isn't: a captive portal, a dead resolver or no route out. WiFi gets
+// bars for its signal (dBm, 0 when unknown) and wired a plug.
+func getNetworkIcon(state, connectivity string, wifi bool, signal int) string {
+	if state != "connected" {
+		return icon("󰖪") + " "
+	}
+	switch connectivity {
+	case connectivityPortal:
+		return icon("󰖟") + " "
+	case connectivityDNS:
+		return icon("󰇖") + " "
+	case connectivityLimited, connectivityNone:
+		return icon("󰤫") + " "
+	}
+	switch {
+	case !wifi:
+		return icon("󰈀") + " "
+	case signal == 0:
+		return icon("󰖩") + " "
+	case signal >= -55:
+		return icon("󰤨") + " "
+	case signal >= -67:
+		return icon("󰤥") + " "
+	case signal >= -75:
+		return icon("󰤢") + " "
 	}
-	return "󰖪 "
+	return icon("󰤟") + " "
 }
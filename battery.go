@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const powerSupplyDir = "/sys/class/power_supply"
+
+// readBatteryPercent computes the charge of the first system battery from
+// sysfs, preferring energy counters over charge counters (which need a
+// voltage conversion) and the firmware's own capacity as a last resort.
+func readBatteryPercent() (float64, bool) {
+	dir, ok := findSystemBattery()
+	if !ok {
+		return 0, false
+	}
+
+	pairs := [][2]string{
+		{"energy_now", "energy_full"},
+		{"charge_now", "charge_full"},
+	}
+	for _, pair := range pairs {
+		now, err1 := readSysfsFloat(dir, pair[0])
+		full, err2 := readSysfsFloat(dir, pair[1])
+		if err1 == nil && err2 == nil && full > 0 {
+			return now / full * 100, true
+		}
+	}
+
+	if capacity, err := readSysfsFloat(dir, "capacity"); err == nil {
+		return capacity, true
+	}
+	return 0, false
+}
+
+func findSystemBattery() (string, bool) {
+	entries, err := os.ReadDir(powerSupplyDir)
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		dir := filepath.Join(powerSupplyDir, entry.Name())
+		if readSysfsString(dir, "type") != "Battery" {
+			continue
+		}
+		// peripherals (mice, controllers) report scope=Device
+		if readSysfsString(dir, "scope") == "Device" {
+			continue
+		}
+		return dir, true
+	}
+	return "", false
+}
+
+func readSysfsString(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readSysfsFloat(dir, name string) (float64, error) {
+	return strconv.ParseFloat(readSysfsString(dir, name), 64)
+}
+
+// chargeLimitFiles are vendor switches that stop charging short of full
+// without saying where; the value is where that firmware usually stops.
+var chargeLimitFiles = map[string]int{
+	"/sys/bus/platform/drivers/ideapad_acpi/*/conservation_mode": 60,
+	"/sys/devices/platform/samsung/battery_life_extender":        80,
+}
+
+// readChargeLimit returns the level the firmware deliberately stops
+// charging at, or 0: the generic charge_control_end_threshold (ASUS,
+// ThinkPad, Framework, Dell), Huawei's threshold pair or one of the vendor
+// switches above.
+func readChargeLimit() int {
+	if dir, ok := findSystemBattery(); ok {
+		for _, name := range []string{"charge_control_end_threshold", "charge_stop_threshold"} {
+			if end, err := readSysfsFloat(dir, name); err == nil && end > 0 && end < 100 {
+				return int(end)
+			}
+		}
+	}
+
+	// "start end", e.g. "40 70"
+	if fields := strings.Fields(readSysfsString("/sys/devices/platform/huawei-wmi", "charge_control_thresholds")); len(fields) == 2 {
+		if end, err := strconv.Atoi(fields[1]); err == nil && end > 0 && end < 100 {
+			return end
+		}
+	}
+
+	for pattern, level := range chargeLimitFiles {
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			if readSysfsString(filepath.Dir(path), filepath.Base(path)) == "1" {
+				return level
+			}
+		}
+	}
+	return 0
+}
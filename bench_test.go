@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func benchModel(b *testing.B) model {
+	b.Helper()
+	config := defaultConfig()
+	if err := config.compile(); err != nil {
+		b.Fatal(err)
+	}
+
+	metrics := NewMetricsCollector(config.Metrics, config.RefreshInterval)
+	metrics.collect(time.Now())
+
+	m := newModel(config, nil, metrics)
+	m.width, m.height = 160, 1
+	m.toggleDebug()
+	for _, mod := range m.modules {
+		m.faults.update(mod, tickMsg(time.Now()))
+	}
+	return m
+}
+
+func BenchmarkView(b *testing.B) {
+	m := benchModel(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		m.View()
+	}
+}
+
+func BenchmarkParseHyprEvent(b *testing.B) {
+	hc := &HyprlandClient{}
+	lines := []string{
+		"workspacev2>>3,3",
+		"activewindowv2>>55d1a3b0c2e0",
+		"openwindow>>55d1a3b0c2e0,3,kitty,~/src, tmux: a, b",
+		"movewindowv2>>55d1a3b0c2e0,4,4",
+		"monitoraddedv2>>1,DP-1,Dell U2720Q",
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, line := range lines {
+			hc.parseEvent(line)
+		}
+	}
+}
+
+func BenchmarkMetricsCollect(b *testing.B) {
+	c := NewMetricsCollector(MetricsConfig{}, 1)
+	now := time.Now()
+	b.ReportAllocs()
+	for b.Loop() {
+		now = now.Add(time.Second)
+		c.collect(now)
+	}
+}
+
+func BenchmarkSparkline(b *testing.B) {
+	values := make([]float64, 600)
+	for i := range values {
+		values[i] = float64(i % 100)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		sparkline(values, 20, 100)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"slices"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// Terminals put cells down left to right in the order they're written, so
+// Hebrew or Arabic titles come out backwards. visualOrder lays a single
+// line out the way the Unicode bidi algorithm would: runs of right-to-left
+// text are reversed with their brackets mirrored, numbers inside them keep
+// their digit order, and a line whose first letter is RTL reads RTL.
+// Explicit embeddings and isolates aren't honoured.
+
+// terminalBidi is set when the terminal reorders text itself (mlterm,
+// konsole), in which case titles are passed through in logical order.
+var terminalBidi bool
+
+type bidiClass int
+
+const (
+	bidiN bidiClass = iota // spaces, punctuation, symbols
+	bidiL
+	bidiR
+	bidiEN // digits
+)
+
+var rtlScripts = []*unicode.RangeTable{unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko}
+
+var mirrored = map[rune]rune{
+	'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{',
+	'<': '>', '>': '<', '«': '»', '»': '«',
+}
+
+func classify(r rune) bidiClass {
+	switch {
+	case r == '\u200f': // right-to-left mark
+		return bidiR
+	case r == '\u200e': // left-to-right mark
+		return bidiL
+	case unicode.IsDigit(r):
+		return bidiEN
+	case unicode.In(r, rtlScripts...):
+		return bidiR
+	case unicode.IsLetter(r):
+		return bidiL
+	}
+	return bidiN
+}
+
+func hasRTL(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, rtlScripts...) && !unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func visualOrder(s string) string {
+	if terminalBidi || !hasRTL(s) {
+		return s
+	}
+
+	// clusters keep combining marks and emoji sequences with their base
+	var clusters []string
+	var classes []bidiClass
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		clusters = append(clusters, g.Str())
+		classes = append(classes, classify(g.Runes()[0]))
+	}
+
+	base := bidiL
+	for _, c := range classes {
+		if c == bidiL || c == bidiR {
+			base = c
+			break
+		}
+	}
+
+	// digits after a left-to-right letter are just more LTR text
+	last := base
+	for i, c := range classes {
+		switch c {
+		case bidiL, bidiR:
+			last = c
+		case bidiEN:
+			if last == bidiL {
+				classes[i] = bidiL
+			}
+		}
+	}
+
+	// neutrals between two runs of the same direction join them, otherwise
+	// they follow the line
+	strong := func(c bidiClass) bidiClass {
+		if c == bidiEN {
+			return bidiR
+		}
+		return c
+	}
+	for i := 0; i < len(classes); {
+		if classes[i] != bidiN {
+			i++
+			continue
+		}
+		j := i
+		for j < len(classes) && classes[j] == bidiN {
+			j++
+		}
+		before, after := base, base
+		if i > 0 {
+			before = strong(classes[i-1])
+		}
+		if j < len(classes) {
+			after = strong(classes[j])
+		}
+		dir := base
+		if before == after {
+			dir = before
+		}
+		for k := i; k < j; k++ {
+			classes[k] = dir
+		}
+		i = j
+	}
+
+	baseLevel := 0
+	if base == bidiR {
+		baseLevel = 1
+	}
+	levels := make([]int, len(classes))
+	high := 0
+	for i, c := range classes {
+		switch {
+		case baseLevel == 0 && c == bidiR:
+			levels[i] = 1
+		case baseLevel == 0 && c == bidiEN:
+			levels[i] = 2
+		case baseLevel == 1 && c != bidiR:
+			levels[i] = 2
+		default:
+			levels[i] = baseLevel
+		}
+		high = max(high, levels[i])
+		if levels[i]%2 == 1 {
+			if m, ok := mirrored[[]rune(clusters[i])[0]]; ok {
+				clusters[i] = string(m)
+			}
+		}
+	}
+
+	// reverse every run at or above each level, highest first
+	for level := high; level >= 1; level-- {
+		for i := 0; i < len(levels); {
+			if levels[i] < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(levels) && levels[j] >= level {
+				j++
+			}
+			slices.Reverse(clusters[i:j])
+			slices.Reverse(levels[i:j])
+			i = j
+		}
+	}
+
+	out := make([]byte, 0, len(s))
+	for _, c := range clusters {
+		out = append(out, c...)
+	}
+	return string(out)
+}
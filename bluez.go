@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// headset battery from BlueZ. Headsets report it over HFP or through a
+// vendor profile; either way PipeWire or BlueZ itself publishes it as an
+// org.bluez.Battery1 on the device.
+
+const (
+	bluezService = "org.bluez"
+	bluezDevice  = bluezService + ".Device1"
+	bluezBattery = bluezService + ".Battery1"
+)
+
+type headsetBattery struct {
+	name    string
+	percent int
+}
+
+// bluezAddress pulls the device address out of a bluetooth sink name, e.g.
+// bluez_output.AA_BB_CC_DD_EE_FF.1 or bluez_sink.AA_BB_CC_DD_EE_FF.a2dp_sink.
+func bluezAddress(sink string) string {
+	rest, ok := strings.CutPrefix(sink, "bluez_output.")
+	if !ok {
+		if rest, ok = strings.CutPrefix(sink, "bluez_sink."); !ok {
+			return ""
+		}
+	}
+	addr, _, _ := strings.Cut(rest, ".")
+	return strings.ReplaceAll(addr, "_", ":")
+}
+
+// fetchHeadsetBattery looks up the connected device with the given address
+// and reports its battery, if it exposes one.
+func fetchHeadsetBattery(address string) (headsetBattery, bool) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return headsetBattery{}, false
+	}
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	if err := conn.Object(bluezService, "/").Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).Store(&objects); err != nil {
+		return headsetBattery{}, false
+	}
+	for _, ifaces := range objects {
+		device, ok := ifaces[bluezDevice]
+		if !ok || !variantBool(device["Connected"]) {
+			continue
+		}
+		if addr, _ := device["Address"].Value().(string); !strings.EqualFold(addr, address) {
+			continue
+		}
+		battery, ok := ifaces[bluezBattery]
+		if !ok {
+			return headsetBattery{}, false
+		}
+		percent, ok := battery["Percentage"].Value().(byte)
+		if !ok {
+			return headsetBattery{}, false
+		}
+		name, _ := device["Alias"].Value().(string)
+		return headsetBattery{name: name, percent: int(percent)}, true
+	}
+	return headsetBattery{}, false
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BorderConfig picks the border of each module: by module id, else by
+// section ("left", "center", "right"), else "*". Types are normal,
+// rounded, thick, double, block, hidden (blank but keeps the space),
+// none, pill (no border; the border color becomes the background) and
+// custom, which draws with Custom's characters.
+type BorderConfig struct {
+	Types  map[string]string `json:"types"`
+	Custom CustomBorder      `json:"custom"`
+}
+
+type CustomBorder struct {
+	Top         string `json:"top"`
+	Bottom      string `json:"bottom"`
+	Left        string `json:"left"`
+	Right       string `json:"right"`
+	TopLeft     string `json:"top_left"`
+	TopRight    string `json:"top_right"`
+	BottomLeft  string `json:"bottom_left"`
+	BottomRight string `json:"bottom_right"`
+}
+
+var (
+	moduleBorders map[string]string
+	customBorder  lipgloss.Border
+)
+
+// setModuleBorders resolves the border type of every placed module.
+func setModuleBorders(config BorderConfig, sections [][]string) {
+	c := config.Custom
+	customBorder = lipgloss.Border{
+		Top: c.Top, Bottom: c.Bottom, Left: c.Left, Right: c.Right,
+		TopLeft: c.TopLeft, TopRight: c.TopRight, BottomLeft: c.BottomLeft, BottomRight: c.BottomRight,
+	}
+	moduleBorders = make(map[string]string)
+	for section, i := range sectionIndex {
+		for _, id := range sections[i] {
+			kind, ok := config.Types[id]
+			if !ok {
+				kind, ok = config.Types[section]
+			}
+			if !ok {
+				kind, ok = config.Types["*"]
+			}
+			if ok {
+				moduleBorders[id] = kind
+			}
+		}
+	}
+}
+
+var borderTypes = map[string]lipgloss.Border{
+	"normal":  lipgloss.NormalBorder(),
+	"rounded": lipgloss.RoundedBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"double":  lipgloss.DoubleBorder(),
+	"block":   lipgloss.BlockBorder(),
+	"hidden":  lipgloss.HiddenBorder(),
+}
+
+func withBorder(style lipgloss.Style, kind string) lipgloss.Style {
+	if b, ok := borderTypes[kind]; ok {
+		return style.Border(b)
+	}
+	switch kind {
+	case "custom":
+		// sides without characters are left off, e.g. just "[" and "]"
+		b := customBorder
+		return style.Border(b, b.Top != "", b.Right != "", b.Bottom != "", b.Left != "")
+	case "none":
+		return style.Border(lipgloss.Border{}, false)
+	case "pill":
+		return style.Border(lipgloss.Border{}, false).
+			Background(style.GetBorderTopForeground()).
+			Foreground(paletteColor("surface"))
+	}
+	return style
+}
+
+func validBorder(kind string) bool {
+	_, ok := borderTypes[kind]
+	return ok || slices.Contains([]string{"custom", "none", "pill"}, kind)
+}
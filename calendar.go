@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CalendarConfig.Dates lists .ics files or plain dates files, one
+// "2026-12-24 Christmas Eve" or yearly "07-14 Sam's birthday" per line.
+type CalendarConfig struct {
+	Dates []string `json:"dates"`
+}
+
+// calendarDate is a named day; year 0 repeats every year.
+type calendarDate struct {
+	year  int
+	month time.Month
+	day   int
+	name  string
+}
+
+func (d calendarDate) on(t time.Time) bool {
+	return (d.year == 0 || d.year == t.Year()) && d.month == t.Month() && d.day == t.Day()
+}
+
+func loadCalendarDates(paths []string) []calendarDate {
+	var dates []calendarDate
+	for _, path := range paths {
+		if strings.HasPrefix(path, "~/") {
+			home, _ := os.UserHomeDir()
+			path = filepath.Join(home, path[2:])
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			log.Printf("calendar: %v", err)
+			continue
+		}
+		if strings.EqualFold(filepath.Ext(path), ".ics") {
+			dates = append(dates, parseICS(f)...)
+		} else {
+			dates = append(dates, parseDatesFile(f)...)
+		}
+		f.Close()
+	}
+	return dates
+}
+
+func parseDatesFile(f *os.File) []calendarDate {
+	var dates []calendarDate
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		when, name, _ := strings.Cut(line, " ")
+		name = strings.TrimSpace(name)
+		if t, err := time.Parse(time.DateOnly, when); err == nil {
+			dates = append(dates, calendarDate{t.Year(), t.Month(), t.Day(), name})
+		} else if t, err := time.Parse("01-02", when); err == nil {
+			dates = append(dates, calendarDate{0, t.Month(), t.Day(), name})
+		}
+	}
+	return dates
+}
+
+// parseICS takes each VEVENT's start day and summary; yearly RRULEs
+// (birthdays, most holiday feeds) repeat.
+func parseICS(f *os.File) []calendarDate {
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// folded continuation lines start with a space or tab
+		if len(lines) > 0 && line != "" && (line[0] == ' ' || line[0] == '\t') {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var dates []calendarDate
+	var event calendarDate
+	var inEvent, yearly bool
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, _, _ = strings.Cut(key, ";")
+		switch {
+		case line == "BEGIN:VEVENT":
+			event, inEvent, yearly = calendarDate{}, true, false
+		case line == "END:VEVENT":
+			if inEvent && event.day > 0 {
+				if yearly {
+					event.year = 0
+				}
+				dates = append(dates, event)
+			}
+			inEvent = false
+		case !inEvent:
+		case key == "DTSTART" && len(value) >= 8:
+			t, err := time.Parse("20060102", value[:8])
+			if err == nil {
+				event.year, event.month, event.day = t.Year(), t.Month(), t.Day()
+			}
+		case key == "RRULE":
+			yearly = strings.Contains(value, "FREQ=YEARLY")
+		case key == "SUMMARY":
+			event.name = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\\`, `\`).Replace(value)
+		}
+	}
+	return dates
+}
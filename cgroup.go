@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupSample is one reading of a cgroup v2 directory. limit is 0 when
+// memory.max is "max".
+type cgroupSample struct {
+	at      time.Time
+	usage   time.Duration // cumulative CPU time from cpu.stat
+	memory  uint64
+	limit   uint64
+	present bool
+}
+
+// resolveCgroup turns the configured name into a directory under
+// /sys/fs/cgroup: paths are taken as they are, systemd units are looked up
+// in the system manager and then the user's.
+func resolveCgroup(name string) (string, bool) {
+	if !isSystemdUnit(name) {
+		dir := filepath.Join(cgroupRoot, strings.TrimPrefix(name, cgroupRoot))
+		_, err := os.Stat(dir)
+		return dir, err == nil
+	}
+	for _, scope := range [][]string{nil, {"--user"}} {
+		args := append(scope, "show", "--property=ControlGroup", "--value", name)
+		out, err := exec.Command("systemctl", args...).Output()
+		if group := strings.TrimSpace(string(out)); err == nil && group != "" {
+			return filepath.Join(cgroupRoot, group), true
+		}
+	}
+	return "", false
+}
+
+func isSystemdUnit(name string) bool {
+	if strings.Contains(name, "/") {
+		return false
+	}
+	for _, suffix := range []string{".slice", ".scope", ".service"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func readCgroup(dir string) cgroupSample {
+	sample := cgroupSample{at: time.Now()}
+	f, err := os.Open(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return sample
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "usage_usec "); ok {
+			usec, _ := strconv.ParseInt(value, 10, 64)
+			sample.usage = time.Duration(usec) * time.Microsecond
+			sample.present = true
+		}
+	}
+	sample.memory, _ = strconv.ParseUint(readSysfsString(dir, "memory.current"), 10, 64)
+	sample.limit, _ = strconv.ParseUint(readSysfsString(dir, "memory.max"), 10, 64)
+	return sample
+}
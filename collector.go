@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+type metricSample struct {
+	at    time.Time
+	value float64
+}
+
+type ringBuffer struct {
+	samples []metricSample
+	next    int
+	full    bool
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &ringBuffer{samples: make([]metricSample, size)}
+}
+
+func (r *ringBuffer) push(s metricSample) {
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *ringBuffer) values() []metricSample {
+	if !r.full {
+		return append([]metricSample(nil), r.samples[:r.next]...)
+	}
+	out := make([]metricSample, 0, len(r.samples))
+	out = append(out, r.samples[r.next:]...)
+	return append(out, r.samples[:r.next]...)
+}
+
+type metricProbe struct {
+	name     string
+	interval time.Duration
+	sample   func() (float64, error)
+	history  *ringBuffer
+	latest   metricSample
+	due      time.Time
+	lastErr  error
+}
+
+// MetricsCollector samples every system metric from a single goroutine on
+// per-metric cadences and keeps a bounded history for each of them. cpu
+// usage in particular must have one caller only, since cpu.Percent with a
+// zero interval diffs against the previous call.
+type MetricsCollector struct {
+	mu       sync.RWMutex
+	probes   map[string]*metricProbe
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewMetricsCollector(config MetricsConfig, defaultInterval int) *MetricsCollector {
+	c := &MetricsCollector{
+		probes: make(map[string]*metricProbe),
+		stop:   make(chan struct{}),
+	}
+
+	history := time.Duration(config.HistorySeconds) * time.Second
+	if history <= 0 {
+		history = 10 * time.Minute
+	}
+
+	builtin := map[string]func() (float64, error){
+		"cpu":         fetchCPUUsage,
+		"memory":      fetchMemoryUsage,
+		"memory.used": fetchMemoryUsed,
+		"disk":        fetchDiskUsage,
+	}
+	for name, sample := range builtin {
+		seconds := defaultInterval
+		if s, ok := config.Intervals[name]; ok {
+			seconds = s
+		}
+		c.Register(name, time.Duration(seconds)*time.Second, history, sample)
+	}
+	return c
+}
+
+func (c *MetricsCollector) Register(name string, interval, history time.Duration, sample func() (float64, error)) {
+	if interval < time.Second {
+		interval = time.Second
+	}
+	c.mu.Lock()
+	c.probes[name] = &metricProbe{
+		name:     name,
+		interval: interval,
+		sample:   sample,
+		history:  newRingBuffer(int(history / interval)),
+	}
+	c.mu.Unlock()
+}
+
+func (c *MetricsCollector) Start() {
+	c.collect(time.Now())
+	go c.run()
+}
+
+func (c *MetricsCollector) Stop() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+func (c *MetricsCollector) run() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			c.collect(now)
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *MetricsCollector) collect(now time.Time) {
+	c.mu.RLock()
+	var due []*metricProbe
+	for _, p := range c.probes {
+		if !now.Before(p.due) {
+			due = append(due, p)
+		}
+	}
+	c.mu.RUnlock()
+
+	for _, p := range due {
+		value, err := p.sample()
+
+		c.mu.Lock()
+		p.due = now.Add(p.interval)
+		p.lastErr = err
+		if err == nil {
+			p.latest = metricSample{at: now, value: value}
+			p.history.push(p.latest)
+		}
+		c.mu.Unlock()
+	}
+}
+
+func (c *MetricsCollector) Latest(name string) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	p, ok := c.probes[name]
+	if !ok || p.latest.at.IsZero() {
+		return 0, false
+	}
+	return p.latest.value, true
+}
+
+func (c *MetricsCollector) History(name string) []metricSample {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	p, ok := c.probes[name]
+	if !ok {
+		return nil
+	}
+	return p.history.values()
+}
+
+func (c *MetricsCollector) Snapshot() map[string]float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	snap := make(map[string]float64, len(c.probes))
+	for name, p := range c.probes {
+		if !p.latest.at.IsZero() {
+			snap[name] = p.latest.value
+		}
+	}
+	return snap
+}
+
+func (c *MetricsCollector) Errors() map[string]error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	errs := make(map[string]error)
+	for name, p := range c.probes {
+		if p.lastErr != nil {
+			errs[name] = p.lastErr
+		}
+	}
+	return errs
+}
+
+func (c *MetricsCollector) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.probes))
+	for name := range c.probes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func historyValues(samples []metricSample) []float64 {
+	values := make([]float64, len(samples))
+	for i, s := range samples {
+		values[i] = s.value
+	}
+	return values
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders the last width values scaled against ceiling
+func sparkline(values []float64, width int, ceiling float64) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	out := make([]rune, len(values))
+	for i, v := range values {
+		idx := 0
+		if ceiling > 0 {
+			idx = int(v / ceiling * float64(len(sparkBlocks)-1))
+		}
+		out[i] = sparkBlocks[max(0, min(len(sparkBlocks)-1, idx))]
+	}
+	return string(out)
+}
+
+func (m model) metricsReply(args []string) string {
+	var v any = m.metrics.Snapshot()
+	if len(args) > 0 {
+		v = historyValues(m.metrics.History(args[0]))
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return string(data)
+}
@@ -2,14 +2,122 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type Config struct {
-	RefreshInterval int      `json:"refresh_interval"`
-	Modules         []string `json:"modules"`
-	Colors          Colors   `json:"colors"`
+	RefreshInterval  int                         `json:"refresh_interval"`
+	Modules          []string                    `json:"modules"`
+	Layout           LayoutConfig                `json:"layout"`
+	Colors           Colors                      `json:"colors"`
+	Visibility       map[string]string           `json:"visibility"`
+	Workspaces       WorkspacesConfig            `json:"workspaces"`
+	Wallpaper        WallpaperConfig             `json:"wallpaper"`
+	Lock             LockConfig                  `json:"lock"`
+	NightLight       NightLightConfig            `json:"nightlight"`
+	Visualizer       VisualizerConfig            `json:"visualizer"`
+	Keyboard         KeyboardConfig              `json:"keyboard"`
+	Battery          BatteryConfig               `json:"battery"`
+	Network          NetworkConfig               `json:"network"`
+	HTTP             HTTPConfig                  `json:"http"`
+	Hide             HideConfig                  `json:"hide"`
+	Metrics          MetricsConfig               `json:"metrics"`
+	Plugins          []PluginConfig              `json:"plugins"`
+	Scripts          []ScriptConfig              `json:"scripts"`
+	Custom           []CustomConfig              `json:"custom"`
+	EventRate        int                         `json:"event_rate"`
+	MaxFPS           int                         `json:"max_fps"`
+	Inline           bool                        `json:"inline"`
+	Bar              BarConfig                   `json:"bar"`
+	Locale           string                      `json:"locale"`
+	Units            UnitsConfig                 `json:"units"`
+	Accessibility    AccessibilityConfig         `json:"accessibility"`
+	Speech           SpeechConfig                `json:"speech"`
+	Tooltips         map[string]string           `json:"tooltips"`
+	Actions          map[string]ClickActions     `json:"actions"`
+	Hyprctl          HyprctlConfig               `json:"hyprctl"`
+	Notifications    NotificationsConfig         `json:"notifications"`
+	Break            BreakConfig                 `json:"break"`
+	TimeTrack        TimeTrackConfig             `json:"timetrack"`
+	Countdown        CountdownConfig             `json:"countdown"`
+	Schedule         ScheduleConfig              `json:"schedule"`
+	WorldClock       WorldClockConfig            `json:"worldclock"`
+	Calendar         CalendarConfig              `json:"calendar"`
+	Focus            FocusConfig                 `json:"focus"`
+	Presentation     PresentationConfig          `json:"presentation"`
+	WorkspaceModules map[string]WorkspaceModules `json:"workspace_modules"`
+	Theme            ThemeConfig                 `json:"theme"`
+	Borders          BorderConfig                `json:"borders"`
+	Cgroup           CgroupConfig                `json:"cgroup"`
+	Security         SecurityConfig              `json:"security"`
+	Backup           BackupConfig                `json:"backup"`
+	Phone            PhoneConfig                 `json:"phone"`
+	Media            MediaConfig                 `json:"media"`
+
+	visibilityRules map[string]exprNode
+}
+
+// WorkspacesConfig.AutoName labels each workspace after its dominant window
+// class, using ClassIcons (matched case-insensitively) or the class itself.
+// Persistent lists workspaces (ids or names) shown even while they don't
+// exist.
+type WorkspacesConfig struct {
+	ShowWindowCount bool              `json:"show_window_count"`
+	AutoName        bool              `json:"auto_name"`
+	ClassIcons      map[string]string `json:"class_icons"`
+	Persistent      []string          `json:"persistent"`
+}
+
+// BarConfig.Density is compact, normal or relaxed. TerminalBidi leaves
+// right-to-left text in logical order for terminals that reorder it
+// themselves.
+type BarConfig struct {
+	Anchor       string `json:"anchor"`
+	Align        string `json:"align"`
+	Density      string `json:"density"`
+	TerminalBidi bool   `json:"terminal_bidi"`
+}
+
+type BatteryConfig struct {
+	PeripheralLow int `json:"peripheral_low"`
+}
+
+// NetworkConfig lists module ids (e.g. "custom:weather" or "plugin:ticker")
+// that depend on the network. Network-backed modules keep their last values
+// with a stale marker while offline; the pause list stops refreshing while
+// the connection is metered. When NetworkManager can't say whether the
+// internet is reachable, CheckURL is fetched and its body compared with
+// CheckResponse to tell a captive portal or broken DNS from a working
+// connection. The probe contacts a third party, so it is off until
+// CheckURL is set, e.g. "http://detectportal.firefox.com/success.txt" with
+// CheckResponse "success".
+type NetworkConfig struct {
+	Modules          []string `json:"modules"`
+	PauseWhenMetered []string `json:"pause_when_metered"`
+	CheckURL         string   `json:"check_url"`
+	CheckResponse    string   `json:"check_response"`
+}
+
+// HyprctlConfig lists the command prefixes plugins may send to Hyprland,
+// e.g. ["dispatch workspace", "dispatch togglespecialworkspace"].
+type HyprctlConfig struct {
+	Allow []string `json:"allow"`
+}
+
+type HideConfig struct {
+	AutoHideSeconds int    `json:"auto_hide_seconds"`
+	Style           string `json:"style"`
+}
+
+type MetricsConfig struct {
+	HistorySeconds int            `json:"history_seconds"`
+	Intervals      map[string]int `json:"intervals"`
+	Sparklines     bool           `json:"sparklines"`
+	MemoryUsed     bool           `json:"memory_used"`
 }
 
 type Colors struct {
@@ -21,27 +129,168 @@ type Colors struct {
 func loadConfig() (*Config, error) {
 	configPath := filepath.Join(os.Getenv("HOME"), ".config", "tui-statusbar", "config.json")
 
+	config := defaultConfig()
 	file, err := os.Open(configPath)
 	if err != nil {
-		return defaultConfig(), nil
+		return config, config.compile()
 	}
 	defer file.Close()
 
-	var config Config
-	if err := json.NewDecoder(file).Decode(&config); err != nil {
+	if err := json.NewDecoder(file).Decode(config); err != nil {
 		return nil, err
 	}
-	return &config, nil
+	if err := config.compile(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) compile() error {
+	if !c.Layout.empty() {
+		modules, err := c.Layout.modules(c)
+		if err != nil {
+			return err
+		}
+		c.Modules = modules
+	}
+	for _, name := range c.Modules {
+		if _, ok := moduleRegistry[name]; !ok {
+			return fmt.Errorf("unknown module %q (available: %s)", name, strings.Join(registeredModules(), ", "))
+		}
+	}
+
+	if _, ok := densities[c.Bar.Density]; c.Bar.Density != "" && !ok {
+		return fmt.Errorf("unknown density %q (compact, normal, relaxed)", c.Bar.Density)
+	}
+	for key, kind := range c.Borders.Types {
+		if !validBorder(kind) {
+			return fmt.Errorf("border for %s: unknown type %q", key, kind)
+		}
+	}
+
+	for _, id := range c.Network.Modules {
+		if !c.knownModule(id) {
+			log.Printf("network.modules: unknown module %q", id)
+		}
+	}
+	for _, id := range c.Network.PauseWhenMetered {
+		if !c.knownModule(id) {
+			log.Printf("network.pause_when_metered: unknown module %q", id)
+		}
+	}
+
+	c.visibilityRules = make(map[string]exprNode)
+	for module, src := range c.Visibility {
+		rule, err := parseExpr(src)
+		if err != nil {
+			return fmt.Errorf("visibility rule for %s: %v", module, err)
+		}
+		c.visibilityRules[module] = rule
+	}
+	return nil
 }
 
 func defaultConfig() *Config {
 	return &Config{
 		RefreshInterval: 1,
-		Modules:         []string{"workspaces", "clock", "cpu", "memory", "battery"},
+		EventRate:       20,
+		Modules:         []string{"workspaces", "layout", "clock", "hyprland", "cpu", "memory", "disk", "network", "battery"},
 		Colors: Colors{
 			Primary: "#D7BAFF",
 			Surface: "#16121B",
 			Text:    "#E9DFEE",
 		},
+		Visibility: map[string]string{
+			"battery": "battery.present || battery.peripherals_low > 0",
+		},
+		Hide: HideConfig{
+			Style: "line",
+		},
+		Metrics: MetricsConfig{
+			HistorySeconds: 600,
+			Intervals: map[string]int{
+				"disk": 30,
+			},
+		},
+		Battery: BatteryConfig{
+			PeripheralLow: 20,
+		},
+		HTTP: HTTPConfig{
+			TimeoutSeconds: 10,
+			Retries:        2,
+			UserAgent:      "tui-bar (+https://github.com/Jlesster/tui-bar)",
+		},
+		Workspaces: WorkspacesConfig{
+			ClassIcons: map[string]string{
+				"firefox":  "󰈹",
+				"chromium": "󰊯",
+				"kitty":    "󰆍",
+				"foot":     "󰞷",
+				"code":     "󰨞",
+				"discord":  "󰙯",
+				"spotify":  "󰓇",
+				"thunar":   "󰉋",
+				"obsidian": "󱓧",
+				"steam":    "󰓓",
+			},
+		},
+		Notifications: NotificationsConfig{
+			Limit:            10,
+			TransientSeconds: 5,
+		},
+		Break: BreakConfig{
+			IntervalMinutes: 20,
+			BreakSeconds:    20,
+			IdleSeconds:     120,
+		},
+		TimeTrack: TimeTrackConfig{
+			Show:        "top",
+			IdleSeconds: 300,
+		},
+		Cgroup: CgroupConfig{
+			WarnPercent: 90,
+		},
+		Backup: BackupConfig{
+			StaleHours: 48,
+		},
+		Media: MediaConfig{
+			MaxWidth:    30,
+			Scroll:      "seek",
+			SeekSeconds: 5,
+		},
+		Security: SecurityConfig{
+			WindowMinutes: 60,
+			Threshold:     5,
+		},
+		Countdown: CountdownConfig{
+			RotateSeconds: 10,
+			WarnDays:      7,
+			CriticalDays:  1,
+		},
+		Focus: FocusConfig{
+			Essentials: []string{"clock", "battery", "focus"},
+		},
+		Presentation: PresentationConfig{
+			Auto:      true,
+			Hide:      []string{"window", "taskbar", "windows", "network", "notifications", "tether"},
+			Recorders: []string{"wf-recorder", "wl-screenrec", "obs", "gpu-screen-recorder", "kooha"},
+		},
+		Theme: ThemeConfig{
+			Name:       "default",
+			Light:      "light",
+			Dark:       "dark",
+			LightFrom:  "08:00",
+			LightUntil: "20:00",
+		},
+		Units: UnitsConfig{
+			Bytes:       "iec",
+			Temperature: "c",
+			Speed:       "kmh",
+			Grouping:    true,
+		},
+		Lock: LockConfig{
+			Command:     []string{"hyprlock"},
+			WarnSeconds: 60,
+		},
 	}
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// windowContainer says what sandbox or container a window's process runs
+// in. kind is empty for ordinary host processes.
+type windowContainer struct {
+	kind string // flatpak, snap, toolbox, distrobox, podman or docker
+	name string // app id or container name, when known
+}
+
+// detectContainer inspects /proc/<pid>: the process's root shows which
+// container files it sees, its cgroup names snaps, and toolbox and
+// distrobox put the container's name in the environment.
+func detectContainer(pid int) windowContainer {
+	if pid <= 0 {
+		return windowContainer{}
+	}
+	proc := fmt.Sprintf("/proc/%d", pid)
+	root := filepath.Join(proc, "root")
+
+	if data, err := os.ReadFile(filepath.Join(root, ".flatpak-info")); err == nil {
+		return windowContainer{kind: "flatpak", name: iniValue(string(data), "Application", "name")}
+	}
+	if data, err := os.ReadFile(filepath.Join(proc, "cgroup")); err == nil {
+		if _, scope, ok := strings.Cut(string(data), "/snap."); ok {
+			name, _, _ := strings.Cut(scope, ".")
+			return windowContainer{kind: "snap", name: name}
+		}
+	}
+
+	env := processEnv(proc)
+	switch {
+	case fileExists(filepath.Join(root, "run", ".toolboxenv")):
+		return windowContainer{kind: "toolbox", name: env["CONTAINER_ID"]}
+	case env["DISTROBOX_ENTER_PATH"] != "" || env["CONTAINER_ID"] != "":
+		return windowContainer{kind: "distrobox", name: env["CONTAINER_ID"]}
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "run", ".containerenv")); err == nil {
+		return windowContainer{kind: "podman", name: strings.Trim(iniValue(string(data), "", "name"), `"`)}
+	}
+	if fileExists(filepath.Join(root, ".dockerenv")) {
+		return windowContainer{kind: "docker"}
+	}
+	return windowContainer{}
+}
+
+func processEnv(proc string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(proc, "environ"))
+	if err != nil {
+		return nil
+	}
+	env := make(map[string]string)
+	for _, kv := range strings.Split(string(data), "\x00") {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// iniValue reads key from the given [section] of an ini-style file; an
+// empty section means keys before any header.
+func iniValue(data, section, key string) string {
+	current := ""
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = line[1 : len(line)-1]
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok && current == section && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
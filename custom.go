@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Custom modules run a shell command every Interval seconds and show what
+// it prints, like waybar's custom modules. Plain output is the text on
+// the first line and optionally a class on the third (the second, the
+// tooltip in waybar, is ignored). Output starting with "{" is read as
+//   {"text": "󰍛 42%", "class": "warning", "percentage": 42}
+// where class may also be a list, of which the first entry counts. Empty
+// text hides the module. The on_* commands run on clicks, after which the
+// module refreshes.
+//
+// Both kinds of command see where the bar is in their environment:
+//   TUI_BAR_MODULE          custom:<name>
+//   TUI_BAR_WIDTH           the bar's width in cells
+//   TUI_BAR_WORKSPACE       the focused workspace's id
+//   TUI_BAR_WORKSPACE_NAME  and its name
+//   TUI_BAR_MONITOR         the monitor it's on
+//   TUI_BAR_WINDOW_CLASS    the focused window's class
+//   TUI_BAR_WINDOW_TITLE    and its title
+
+const customTimeout = 10 * time.Second
+
+type CustomConfig struct {
+	Name     string `json:"name"`
+	Exec     string `json:"exec"`
+	Interval int    `json:"interval"`
+	Position string `json:"position"`
+	ClickActions
+}
+
+type customResult struct {
+	text       string
+	class      string
+	percentage int
+	err        error
+}
+
+type CustomModule struct {
+	ctx     *moduleContext
+	config  CustomConfig
+	result  customResult
+	ready   bool
+	running bool
+	elapsed time.Duration
+	width   int
+}
+
+func newCustomModule(ctx *moduleContext, config CustomConfig) *CustomModule {
+	if config.Interval <= 0 {
+		config.Interval = 5
+	}
+	return &CustomModule{ctx: ctx, config: config}
+}
+
+func (m *CustomModule) Name() string {
+	return "custom:" + m.config.Name
+}
+
+func (m *CustomModule) Init() tea.Cmd {
+	return m.run()
+}
+
+// run starts the command unless the previous run is still going.
+func (m *CustomModule) run() tea.Cmd {
+	if m.running {
+		return nil
+	}
+	m.running = true
+	command, env := m.config.Exec, m.env()
+	return moduleCmd(m.Name(), func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), customTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Env = env()
+		out, err := cmd.Output()
+		if err != nil {
+			return customResult{err: err}
+		}
+		return parseCustomOutput(out)
+	})
+}
+
+func (m *CustomModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.elapsed += time.Second
+		if m.elapsed < time.Duration(m.config.Interval)*time.Second {
+			return nil
+		}
+		m.elapsed = 0
+		return m.run()
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case customResult:
+		if msg.err != nil {
+			log.Printf("custom %s: %v", m.config.Name, msg.err)
+		}
+		m.result = msg
+		m.ready = true
+		m.running = false
+	}
+	return nil
+}
+
+func (m *CustomModule) Ready() bool {
+	return m.ready
+}
+
+func (m *CustomModule) Vars(vars exprVars) {
+	vars[m.Name()] = m.result.text
+	vars[m.Name()+".percentage"] = m.result.percentage
+}
+
+func (m *CustomModule) Render() string {
+	if !m.ready {
+		return placeholder("󰆍")
+	}
+	if m.result.err != nil {
+		return icon("󰀦") + " " + m.config.Name
+	}
+	return m.result.text
+}
+
+func (m *CustomModule) Style() lipgloss.Style {
+	if m.result.err != nil {
+		return criticalStyle
+	}
+	return classStyle(m.result.class)
+}
+
+func (m *CustomModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	command := m.config.command(msg.Type)
+	if command == "" {
+		return nil
+	}
+	m.elapsed = 0
+	name, env := m.Name(), m.env()
+	return tea.Sequence(func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), customTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Env = env()
+		if err := cmd.Run(); err != nil {
+			log.Printf("%s: %q failed: %v", name, command, err)
+		}
+		return nil
+	}, m.run())
+}
+
+// env returns a function building the commands' environment; it queries
+// Hyprland, so it's called off the UI goroutine.
+func (m *CustomModule) env() func() []string {
+	hc, name, width := m.ctx.hypr, m.Name(), m.width
+	return func() []string {
+		env := append(os.Environ(), "TUI_BAR_MODULE="+name, "TUI_BAR_WIDTH="+strconv.Itoa(width))
+		if hc == nil {
+			return env
+		}
+		if ws, err := hc.GetActiveWorkspace(); err == nil {
+			env = append(env,
+				"TUI_BAR_WORKSPACE="+strconv.Itoa(ws.ID),
+				"TUI_BAR_WORKSPACE_NAME="+ws.Name,
+				"TUI_BAR_MONITOR="+ws.Monitor)
+		}
+		if win, err := hc.GetActiveWindow(); err == nil {
+			env = append(env, "TUI_BAR_WINDOW_CLASS="+win.Class, "TUI_BAR_WINDOW_TITLE="+win.Title)
+		}
+		return env
+	}
+}
+
+func parseCustomOutput(out []byte) customResult {
+	out = bytes.TrimSpace(out)
+	if bytes.HasPrefix(out, []byte("{")) {
+		var data struct {
+			Text       string          `json:"text"`
+			Class      json.RawMessage `json:"class"`
+			Percentage float64         `json:"percentage"`
+		}
+		if err := json.Unmarshal(out, &data); err != nil {
+			return customResult{err: fmt.Errorf("bad json output: %w", err)}
+		}
+		result := customResult{text: sanitizeText(data.Text), percentage: int(data.Percentage)}
+		var classes []string
+		if json.Unmarshal(data.Class, &result.class) != nil && json.Unmarshal(data.Class, &classes) == nil && len(classes) > 0 {
+			result.class = classes[0]
+		}
+		return result
+	}
+
+	lines := strings.Split(string(out), "\n")
+	result := customResult{text: sanitizeText(lines[0])}
+	if len(lines) >= 3 {
+		result.class = strings.TrimSpace(lines[2])
+	}
+	return result
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCustomErrorRender(t *testing.T) {
+	m := newCustomModule(&moduleContext{}, CustomConfig{Name: "weather"})
+	m.ready = true
+	m.result = customResult{err: errors.New("exit status 1")}
+	if got, want := m.Render(), "\U000F0026 weather"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	textLabels = true
+	defer func() { textLabels = false }()
+	if got, want := m.Render(), "error weather"; got != want {
+		t.Errorf("Render() with text labels = %q, want %q", got, want)
+	}
+}
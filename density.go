@@ -0,0 +1,22 @@
+package main
+
+// density profiles: the horizontal padding inside padded styles and the
+// gap between modules
+type densityProfile struct {
+	padding int
+	gap     int
+}
+
+var densities = map[string]densityProfile{
+	"compact": {padding: 0, gap: 0},
+	"normal":  {padding: 1, gap: 0},
+	"relaxed": {padding: 2, gap: 1},
+}
+
+var density = densities["normal"]
+
+func setDensity(name string) {
+	if d, ok := densities[name]; ok {
+		density = d
+	}
+}
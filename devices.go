@@ -0,0 +1,77 @@
+package main
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+// peripheral batteries from UPower: game controllers and styluses report
+// their charge there rather than under /sys/class/power_supply
+
+const (
+	upowerService   = "org.freedesktop.UPower"
+	upowerPath      = "/org/freedesktop/UPower"
+	upowerDevice    = "org.freedesktop.UPower.Device"
+	upowerGaming    = 12
+	upowerPen       = 13
+	upowerCharging  = 1
+	upowerFullState = 4
+)
+
+type peripheralBattery struct {
+	name     string
+	kind     string
+	level    int
+	charging bool
+}
+
+var peripheralKinds = map[uint32]string{
+	upowerGaming: "controller",
+	upowerPen:    "stylus",
+}
+
+func fetchPeripheralBatteries() ([]peripheralBattery, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []dbus.ObjectPath
+	if err := conn.Object(upowerService, upowerPath).Call(upowerService+".EnumerateDevices", 0).Store(&paths); err != nil {
+		return nil, err
+	}
+
+	var batteries []peripheralBattery
+	for _, path := range paths {
+		var props map[string]dbus.Variant
+		err := conn.Object(upowerService, path).Call("org.freedesktop.DBus.Properties.GetAll", 0, upowerDevice).Store(&props)
+		if err != nil {
+			continue
+		}
+
+		kindID, _ := props["Type"].Value().(uint32)
+		kind, ok := peripheralKinds[kindID]
+		if !ok {
+			continue
+		}
+		if present, ok := props["IsPresent"].Value().(bool); ok && !present {
+			continue
+		}
+
+		b := peripheralBattery{kind: kind}
+		b.name, _ = props["Model"].Value().(string)
+		if percentage, ok := props["Percentage"].Value().(float64); ok {
+			b.level = clampPercent(percentage)
+		}
+		state, _ := props["State"].Value().(uint32)
+		b.charging = state == upowerCharging || state == upowerFullState
+		batteries = append(batteries, b)
+	}
+	return batteries, nil
+}
+
+func peripheralIcon(kind string) string {
+	if kind == "stylus" {
+		return icon("󰏪")
+	}
+	return icon("󰊴")
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type hyprEventMsg HyprlandEvent
+
+// while socket2 is up, compositor state only gets re-queried this often (in
+// ticks) to catch anything the events didn't cover
+const hyprResync = 30
+
+// hyprPoll reports whether a module should query hyprctl on this tick.
+func hyprPoll(hc *HyprlandClient, ticks int) bool {
+	return !hc.Listening() || ticks%hyprResync == 0
+}
+
+// hyprEventBatchMsg carries every compositor event that arrived within one
+// render interval, so a burst (e.g. moving many windows) costs one render
+// instead of dozens.
+type hyprEventBatchMsg struct {
+	events []hyprEventMsg
+	at     time.Time
+}
+
+// events that describe current state rather than a change; only the most
+// recent one in a batch matters
+var latestWinsEvents = map[string]bool{
+	"workspace":      true,
+	"activewindow":   true,
+	"activewindowv2": true,
+	"focusedmon":     true,
+	"focusedmonv2":   true,
+	"activelayout":   true,
+	"submap":         true,
+}
+
+func (b *hyprEventBatchMsg) add(event HyprlandEvent) {
+	if n := len(b.events); n > 0 && sameEvent(HyprlandEvent(b.events[n-1]), event) {
+		return
+	}
+	if latestWinsEvents[event.Type] {
+		b.events = slices.DeleteFunc(b.events, func(e hyprEventMsg) bool {
+			return e.Type == event.Type
+		})
+	}
+	b.events = append(b.events, hyprEventMsg(event))
+}
+
+func sameEvent(a, b HyprlandEvent) bool {
+	return a.Type == b.Type && slices.Equal(a.Data, b.Data)
+}
+
+func (m model) eventInterval() time.Duration {
+	rate := m.config.EventRate
+	if rate <= 0 {
+		rate = 20
+	}
+	return time.Second / time.Duration(rate)
+}
+
+// waitForHyprlandEvents delivers the first event of a quiet period right
+// away, then coalesces whatever follows until the interval has passed.
+func (m model) waitForHyprlandEvents() tea.Cmd {
+	events := m.hyprEvents
+	if events == nil {
+		return nil
+	}
+	earliest := m.lastEvents.Add(m.eventInterval())
+
+	return func() tea.Msg {
+		var batch hyprEventBatchMsg
+		event, ok := <-events
+		if !ok {
+			return nil
+		}
+		batch.add(event)
+
+		wait := time.NewTimer(time.Until(earliest))
+		defer wait.Stop()
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					batch.at = time.Now()
+					return batch
+				}
+				batch.add(event)
+				continue
+			default:
+			}
+
+			select {
+			case event, ok := <-events:
+				if !ok {
+					batch.at = time.Now()
+					return batch
+				}
+				batch.add(event)
+			case <-wait.C:
+				batch.at = time.Now()
+				return batch
+			}
+		}
+	}
+}
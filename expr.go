@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// small expression language used by config rules, e.g.
+//   battery.present && battery.level < 95
+//   updates.count > 0 || network.state == "connected"
+
+type exprVars map[string]any
+
+type exprNode interface {
+	eval(vars exprVars) any
+}
+
+type exprLiteral struct{ value any }
+type exprIdent struct{ name string }
+type exprNot struct{ operand exprNode }
+type exprBinary struct {
+	op          string
+	left, right exprNode
+}
+
+func (e exprLiteral) eval(vars exprVars) any { return e.value }
+func (e exprIdent) eval(vars exprVars) any   { return vars[e.name] }
+func (e exprNot) eval(vars exprVars) any     { return !truthy(e.operand.eval(vars)) }
+
+func (e exprBinary) eval(vars exprVars) any {
+	switch e.op {
+	case "&&":
+		return truthy(e.left.eval(vars)) && truthy(e.right.eval(vars))
+	case "||":
+		return truthy(e.left.eval(vars)) || truthy(e.right.eval(vars))
+	}
+
+	l, r := e.left.eval(vars), e.right.eval(vars)
+	switch e.op {
+	case "==":
+		return exprEqual(l, r)
+	case "!=":
+		return !exprEqual(l, r)
+	}
+
+	lf, lok := toNumber(l)
+	rf, rok := toNumber(r)
+	if !lok || !rok {
+		return false
+	}
+	switch e.op {
+	case "<":
+		return lf < rf
+	case "<=":
+		return lf <= rf
+	case ">":
+		return lf > rf
+	case ">=":
+		return lf >= rf
+	}
+	return false
+}
+
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+	if f, ok := toNumber(v); ok {
+		return f != 0
+	}
+	return true
+}
+
+func toNumber(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func exprEqual(l, r any) bool {
+	if lf, ok := toNumber(l); ok {
+		if rf, ok := toNumber(r); ok {
+			return lf == rf
+		}
+	}
+	return fmt.Sprint(l) == fmt.Sprint(r)
+}
+
+func evalBool(node exprNode, vars exprVars) bool {
+	if node == nil {
+		return true
+	}
+	return truthy(node.eval(vars))
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseExpr(src string) (exprNode, error) {
+	tokens, err := tokenizeExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	p := &exprParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in %q", p.tokens[p.pos], src)
+	}
+	return node, nil
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = exprBinary{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = exprBinary{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if p.peek() == "!" {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return exprNot{operand: operand}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *exprParser) parseCompare() (exprNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return exprBinary{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		return node, nil
+	case tok == "true":
+		return exprLiteral{value: true}, nil
+	case tok == "false":
+		return exprLiteral{value: false}, nil
+	case tok[0] == '"' || tok[0] == '\'':
+		return exprLiteral{value: tok[1 : len(tok)-1]}, nil
+	case unicode.IsDigit(rune(tok[0])) || tok[0] == '-':
+		f, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok)
+		}
+		return exprLiteral{value: f}, nil
+	case isIdentStart(rune(tok[0])):
+		return exprIdent{name: tok}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok)
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '.'
+}
+
+func tokenizeExpr(src string) ([]string, error) {
+	var tokens []string
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string in %q", src)
+			}
+			tokens = append(tokens, string(runes[i:j+1]))
+			i = j + 1
+
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+
+		case isIdentStart(r):
+			j := i + 1
+			for j < len(runes) && isIdentPart(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+
+		default:
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch {
+			case two == "&&" || two == "||" || two == "==" || two == "!=" || two == "<=" || two == ">=":
+				tokens = append(tokens, two)
+				i += 2
+			case strings.ContainsRune("!<>()", r):
+				tokens = append(tokens, string(r))
+				i++
+			default:
+				return nil, fmt.Errorf("unexpected character %q in %q", r, src)
+			}
+		}
+	}
+	return tokens, nil
+}
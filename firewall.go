@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// firewallState says which firewall is in charge and whether it's
+// enforcing. backend is empty when none of them is installed.
+type firewallState struct {
+	backend string
+	active  bool
+	zone    string // firewalld's default zone
+}
+
+const firewalldService = "org.fedoraproject.FirewallD1"
+
+// fetchFirewallState asks firewalld over D-Bus, then reads ufw's config
+// and finally checks the nftables unit. Rulesets themselves need root, so
+// an active nftables service is taken at its word.
+func fetchFirewallState() firewallState {
+	if state, ok := firewalldState(); ok {
+		return state
+	}
+	if data, err := os.ReadFile("/etc/ufw/ufw.conf"); err == nil {
+		enabled := false
+		for _, line := range strings.Split(string(data), "\n") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(line), "ENABLED="); ok {
+				enabled = strings.EqualFold(strings.Trim(value, `"'`), "yes")
+			}
+		}
+		return firewallState{backend: "ufw", active: enabled}
+	}
+	if _, err := exec.LookPath("nft"); err == nil {
+		active := exec.Command("systemctl", "is-active", "--quiet", "nftables.service").Run() == nil
+		return firewallState{backend: "nftables", active: active}
+	}
+	return firewallState{}
+}
+
+func firewalldState() (firewallState, bool) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return firewallState{}, false
+	}
+	var owned bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, firewalldService).Store(&owned); err != nil || !owned {
+		return firewallState{}, false
+	}
+	obj := conn.Object(firewalldService, "/org/fedoraproject/FirewallD1")
+	state := firewallState{backend: "firewalld"}
+	if v, err := obj.GetProperty(firewalldService + ".state"); err == nil {
+		s, _ := v.Value().(string)
+		state.active = s == "RUNNING"
+	}
+	_ = obj.Call(firewalldService+".getDefaultZone", 0).Store(&state.zone)
+	return state, true
+}
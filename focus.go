@@ -0,0 +1,53 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FocusConfig.Essentials are the modules focus mode leaves as they are;
+// everything else is dimmed down to its icon, losing counts and badges.
+type FocusConfig struct {
+	Essentials []string `json:"essentials"`
+}
+
+type focusToggleMsg struct{}
+
+// focusMsg tells modules focus mode changed.
+type focusMsg bool
+
+func toggleFocus() tea.Cmd {
+	return func() tea.Msg { return focusToggleMsg{} }
+}
+
+func (m model) setFocus(focus bool) (model, tea.Cmd) {
+	if focus == m.focus {
+		return m, nil
+	}
+	m.focus = focus
+	return m, m.broadcast(focusMsg(focus))
+}
+
+func (m model) dimmed(id string) bool {
+	return m.focus && !slices.Contains(m.config.Focus.Essentials, id)
+}
+
+func (m model) renderDimmed(mod Module) segments {
+	var s segments
+	ok := m.faults.guard(mod.Name(), "render", func() {
+		content := mod.Render()
+		// the leading glyph, unless the module is all segments (workspaces)
+		if _, segmented := mod.(segmentedModule); !segmented {
+			content, _, _ = strings.Cut(content, " ")
+		}
+		if content != "" {
+			s.add(mod.Name(), dimStyle.Render(content))
+		}
+	})
+	if !ok {
+		return m.faults.render(mod)
+	}
+	return s
+}
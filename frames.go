@@ -0,0 +1,74 @@
+package main
+
+import (
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+// the bar is always on screen, so every frame should stay well inside
+// this budget; frames that don't are counted
+const frameBudget = 2 * time.Millisecond
+
+type frameStats struct {
+	mu        sync.Mutex
+	frames    uint64
+	overrun   uint64
+	last      time.Duration
+	avg       time.Duration
+	max       time.Duration
+	lastAlloc uint64
+}
+
+type frameSnapshot struct {
+	frames, overrun uint64
+	last, avg, max  time.Duration
+	allocBytes      uint64
+}
+
+var allocSample = []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}}
+
+func heapAllocated() uint64 {
+	metrics.Read(allocSample)
+	return allocSample[0].Value.Uint64()
+}
+
+// measure starts timing a frame; call the returned func when it's done.
+func (f *frameStats) measure() func() {
+	start := time.Now()
+	before := heapAllocated()
+	return func() {
+		f.record(time.Since(start), heapAllocated()-before)
+	}
+}
+
+func (f *frameStats) record(d time.Duration, allocBytes uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.frames++
+	f.last = d
+	f.lastAlloc = allocBytes
+	if f.avg == 0 {
+		f.avg = d
+	} else {
+		f.avg += (d - f.avg) / 8
+	}
+	f.max = max(f.max, d)
+	if d > frameBudget {
+		f.overrun++
+	}
+}
+
+func (f *frameStats) snapshot() frameSnapshot {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return frameSnapshot{
+		frames:     f.frames,
+		overrun:    f.overrun,
+		last:       f.last,
+		avg:        f.avg,
+		max:        f.max,
+		allocBytes: f.lastAlloc,
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// gpuState is the first GPU's load and memory. busy is -1 when the driver
+// doesn't say.
+type gpuState struct {
+	busy      int
+	vramUsed  uint64
+	vramTotal uint64
+	processes []gpuProcess
+}
+
+type gpuProcess struct {
+	pid  int
+	name string
+	vram uint64
+}
+
+// fetchGPUState reads amdgpu's sysfs counters or asks nvidia-smi, and with
+// processes also lists who holds GPU memory.
+func fetchGPUState(withProcesses bool) gpuState {
+	state := gpuState{busy: -1}
+	if busy, ok := readAMDGPU(&state); !ok {
+		readNvidiaGPU(&state)
+	} else {
+		state.busy = busy
+	}
+	if withProcesses {
+		state.processes = gpuProcesses()
+	}
+	return state
+}
+
+func readAMDGPU(state *gpuState) (int, bool) {
+	cards, _ := filepath.Glob("/sys/class/drm/card[0-9]*/device/gpu_busy_percent")
+	if len(cards) == 0 {
+		return 0, false
+	}
+	dir := filepath.Dir(cards[0])
+	busy, err := strconv.Atoi(readSysfsString(dir, "gpu_busy_percent"))
+	if err != nil {
+		return 0, false
+	}
+	state.vramUsed, _ = strconv.ParseUint(readSysfsString(dir, "mem_info_vram_used"), 10, 64)
+	state.vramTotal, _ = strconv.ParseUint(readSysfsString(dir, "mem_info_vram_total"), 10, 64)
+	return busy, true
+}
+
+func readNvidiaGPU(state *gpuState) {
+	if _, err := os.Stat("/proc/driver/nvidia"); err != nil {
+		return
+	}
+	out, err := exec.Command("nvidia-smi", "--query-gpu=utilization.gpu,memory.used,memory.total", "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		return
+	}
+	line, _, _ := strings.Cut(string(out), "\n")
+	fields := strings.Split(line, ",")
+	if len(fields) != 3 {
+		return
+	}
+	state.busy, _ = strconv.Atoi(strings.TrimSpace(fields[0]))
+	used, _ := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 64)
+	total, _ := strconv.ParseUint(strings.TrimSpace(fields[2]), 10, 64)
+	state.vramUsed, state.vramTotal = used<<20, total<<20
+}
+
+// gpuProcesses walks the DRM fdinfo of every process (amdgpu, i915, xe,
+// nouveau, nvidia-open), counting each client once however many fds share
+// it, and falls back to nvidia-smi for the proprietary driver. Largest
+// first.
+func gpuProcesses() []gpuProcess {
+	byPid := map[int]*gpuProcess{}
+	seen := map[string]bool{}
+	fdinfos, _ := filepath.Glob("/proc/[0-9]*/fdinfo/*")
+	for _, path := range fdinfos {
+		client, vram, ok := readDRMFdinfo(path)
+		if !ok || seen[client] {
+			continue
+		}
+		seen[client] = true
+		pid, _ := strconv.Atoi(strings.Split(path, "/")[2])
+		p, ok := byPid[pid]
+		if !ok {
+			p = &gpuProcess{pid: pid, name: readSysfsString(filepath.Join("/proc", strconv.Itoa(pid)), "comm")}
+			byPid[pid] = p
+		}
+		p.vram += vram
+	}
+
+	if len(byPid) == 0 {
+		for _, p := range nvidiaProcesses() {
+			byPid[p.pid] = &p
+		}
+	}
+
+	processes := make([]gpuProcess, 0, len(byPid))
+	for _, p := range byPid {
+		processes = append(processes, *p)
+	}
+	slices.SortFunc(processes, func(a, b gpuProcess) int {
+		return cmp.Or(cmp.Compare(b.vram, a.vram), cmp.Compare(a.pid, b.pid))
+	})
+	return processes
+}
+
+// readDRMFdinfo parses one fdinfo file, returning the DRM client (device
+// and client id) and the VRAM it holds.
+func readDRMFdinfo(path string) (string, uint64, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, false
+	}
+	defer f.Close()
+
+	var pdev, client string
+	var vram uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "drm-pdev":
+			pdev = value
+		case "drm-client-id":
+			client = value
+		case "drm-memory-vram", "drm-resident-vram0", "drm-resident-local0":
+			vram += parseFdinfoBytes(value)
+		}
+	}
+	if client == "" {
+		return "", 0, false
+	}
+	return pdev + "/" + client, vram, true
+}
+
+// parseFdinfoBytes reads "123 KiB" style sizes.
+func parseFdinfoBytes(value string) uint64 {
+	number, unit, _ := strings.Cut(value, " ")
+	n, _ := strconv.ParseUint(number, 10, 64)
+	switch unit {
+	case "KiB":
+		return n << 10
+	case "MiB":
+		return n << 20
+	case "GiB":
+		return n << 30
+	}
+	return n
+}
+
+func nvidiaProcesses() []gpuProcess {
+	if _, err := os.Stat("/proc/driver/nvidia"); err != nil {
+		return nil
+	}
+	out, err := exec.Command("nvidia-smi", "--query-compute-apps=pid,process_name,used_memory", "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		return nil
+	}
+	var processes []gpuProcess
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			continue
+		}
+		pid, _ := strconv.Atoi(strings.TrimSpace(fields[0]))
+		used, _ := strconv.ParseUint(strings.TrimSpace(fields[2]), 10, 64)
+		processes = append(processes, gpuProcess{pid: pid, name: filepath.Base(strings.TrimSpace(fields[1])), vram: used << 20})
+	}
+	return processes
+}
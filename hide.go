@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m model) setHidden(hidden bool) (model, tea.Cmd) {
+	m.lastActivity = time.Now()
+	if hidden == m.hidden {
+		return m, nil
+	}
+	m.hidden = hidden
+
+	if m.wantsAllMotion() {
+		return m, tea.EnableMouseAllMotion
+	}
+	return m, tea.EnableMouseCellMotion
+}
+
+func (m model) checkAutoHide(now time.Time) (model, tea.Cmd) {
+	timeout := time.Duration(m.config.Hide.AutoHideSeconds) * time.Second
+	if timeout <= 0 || m.hidden || now.Sub(m.lastActivity) < timeout {
+		return m, nil
+	}
+	return m.setHidden(true)
+}
+
+func (m model) renderHidden() string {
+	if m.config.Hide.Style == "blank" {
+		return strings.Repeat(" ", m.width)
+	}
+	return hiddenLineStyle.Render(strings.Repeat("─", m.width))
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// shared HTTP layer for network-backed modules. Responses are cached per
+// module under $XDG_CACHE_HOME/tui-bar/http/<module>/ and revalidated with
+// ETag / Last-Modified; failed requests are retried with exponential
+// backoff, and a URL that keeps failing is left alone for a while.
+
+const (
+	httpMaxBackoff = 5 * time.Minute
+	httpMaxBody    = 4 << 20
+)
+
+type HTTPConfig struct {
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	Retries        int    `json:"retries"`
+	UserAgent      string `json:"user_agent"`
+}
+
+type httpCacheEntry struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Fetched      time.Time `json:"fetched"`
+	Body         []byte    `json:"body"`
+}
+
+type httpStatusError struct {
+	status     int
+	retryAfter time.Duration
+}
+
+func (e httpStatusError) Error() string {
+	return fmt.Sprintf("http status %d", e.status)
+}
+
+type HTTPClient struct {
+	client    *http.Client
+	retries   int
+	userAgent string
+
+	mu      sync.Mutex
+	backoff map[string]httpBackoff
+}
+
+type httpBackoff struct {
+	failures int
+	until    time.Time
+}
+
+func newHTTPClient(config HTTPConfig) *HTTPClient {
+	timeout := time.Duration(config.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	userAgent := config.UserAgent
+	if userAgent == "" {
+		userAgent = "tui-bar"
+	}
+	return &HTTPClient{
+		client:    &http.Client{Timeout: timeout},
+		retries:   max(config.Retries, 0),
+		userAgent: userAgent,
+		backoff:   make(map[string]httpBackoff),
+	}
+}
+
+func httpCachePath(module, url string) string {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".cache")
+	}
+	sum := sha1.Sum([]byte(url))
+	return filepath.Join(dir, "tui-bar", "http", module, hex.EncodeToString(sum[:])+".json")
+}
+
+func loadHTTPCache(path string) (httpCacheEntry, bool) {
+	var entry httpCacheEntry
+	if path == "" {
+		return entry, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return entry, false
+	}
+	return entry, json.Unmarshal(data, &entry) == nil
+}
+
+func storeHTTPCache(path string, entry httpCacheEntry) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Get fetches url on behalf of module. If the request fails, the cached
+// body (if any) is returned along with the error so the caller can keep
+// showing it; maxAge > 0 serves a fresh enough cache without a request.
+func (c *HTTPClient) Get(ctx context.Context, module, url string, maxAge time.Duration) ([]byte, time.Time, error) {
+	path := httpCachePath(module, url)
+	cached, haveCache := loadHTTPCache(path)
+	if haveCache && maxAge > 0 && time.Since(cached.Fetched) < maxAge {
+		return cached.Body, cached.Fetched, nil
+	}
+
+	if wait := c.backingOff(url); wait > 0 {
+		return cached.Body, cached.Fetched, fmt.Errorf("backing off for %s", wait.Round(time.Second))
+	}
+
+	entry, err := c.fetch(ctx, url, cached, haveCache)
+	if err != nil {
+		var retryAfter time.Duration
+		if se, ok := err.(httpStatusError); ok {
+			retryAfter = se.retryAfter
+		}
+		c.failed(url, retryAfter)
+		return cached.Body, cached.Fetched, err
+	}
+	c.succeeded(url)
+	if err := storeHTTPCache(path, entry); err != nil {
+		log.Printf("http cache for %s: %v", module, err)
+	}
+	return entry.Body, entry.Fetched, nil
+}
+
+func (c *HTTPClient) fetch(ctx context.Context, url string, cached httpCacheEntry, haveCache bool) (httpCacheEntry, error) {
+	delay := 500 * time.Millisecond
+	var err error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(delay):
+				delay *= 2
+			case <-ctx.Done():
+				return cached, ctx.Err()
+			}
+		}
+
+		var entry httpCacheEntry
+		entry, err = c.do(ctx, url, cached, haveCache)
+		if err == nil {
+			return entry, nil
+		}
+		// client errors won't go away by asking again, and a server asking
+		// us to come back later gets its wish
+		if se, ok := err.(httpStatusError); ok && (se.status < 500 && se.status != http.StatusTooManyRequests || se.retryAfter > 0) {
+			break
+		}
+	}
+	return cached, err
+}
+
+func (c *HTTPClient) do(ctx context.Context, url string, cached httpCacheEntry, haveCache bool) (httpCacheEntry, error) {
+	header := make(http.Header)
+	if haveCache {
+		if cached.ETag != "" {
+			header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := c.get(ctx, url, header)
+	if err != nil {
+		return cached, err
+	}
+	defer resp.Body.Close()
+
+	now := time.Now()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && haveCache:
+		cached.Fetched = now
+		return cached, nil
+	case resp.StatusCode != http.StatusOK:
+		return cached, httpStatusError{
+			status:     resp.StatusCode,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, httpMaxBody))
+	if err != nil {
+		return cached, err
+	}
+	return httpCacheEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Fetched:      now,
+		Body:         body,
+	}, nil
+}
+
+// get makes one uncached request with the shared user agent; the caller
+// closes the body.
+func (c *HTTPClient) get(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	return c.client.Do(req)
+}
+
+// withoutRedirects returns a copy that hands back redirects instead of
+// following them.
+func (c *HTTPClient) withoutRedirects() *HTTPClient {
+	client := *c.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &HTTPClient{client: &client, retries: c.retries, userAgent: c.userAgent, backoff: make(map[string]httpBackoff)}
+}
+
+func (c *HTTPClient) backingOff(url string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Until(c.backoff[url].until)
+}
+
+// failed doubles the time a URL is left alone after each failed Get, from
+// 30s up to httpMaxBackoff, unless the server said how long to wait.
+func (c *HTTPClient) failed(url string, retryAfter time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b := c.backoff[url]
+	b.failures++
+	wait := min(30*time.Second<<min(b.failures-1, 10), httpMaxBackoff)
+	if retryAfter > 0 {
+		wait = retryAfter
+	}
+	b.until = time.Now().Add(wait)
+	c.backoff[url] = b
+}
+
+func (c *HTTPClient) succeeded(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.backoff, url)
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if s, err := strconv.Atoi(value); err == nil {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
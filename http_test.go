@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPGetRevalidatesWithETag(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	var requests atomic.Int32
+	var userAgent, ifNoneMatch string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		userAgent, ifNoneMatch = r.UserAgent(), r.Header.Get("If-None-Match")
+		if ifNoneMatch == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	c := newHTTPClient(HTTPConfig{UserAgent: "tui-bar-test"})
+	body, _, err := c.Get(context.Background(), "test", srv.URL, 0)
+	if err != nil || string(body) != "hello" {
+		t.Fatalf("first Get = %q, %v", body, err)
+	}
+	if userAgent != "tui-bar-test" {
+		t.Errorf("User-Agent = %q", userAgent)
+	}
+	if _, err := os.Stat(httpCachePath("test", srv.URL)); err != nil {
+		t.Errorf("no cache file: %v", err)
+	}
+
+	body, _, err = c.Get(context.Background(), "test", srv.URL, 0)
+	if err != nil || string(body) != "hello" {
+		t.Fatalf("revalidated Get = %q, %v", body, err)
+	}
+	if ifNoneMatch != `"v1"` {
+		t.Errorf("If-None-Match = %q, want the cached ETag", ifNoneMatch)
+	}
+
+	if _, _, err := c.Get(context.Background(), "test", srv.URL, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d requests, want 2: a fresh cache shouldn't be refetched", n)
+	}
+}
+
+func TestHTTPGetRetriesServerErrors(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := newHTTPClient(HTTPConfig{Retries: 2})
+	body, _, err := c.Get(context.Background(), "test", srv.URL, 0)
+	if err != nil || string(body) != "ok" {
+		t.Fatalf("Get = %q, %v", body, err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d requests, want 2", n)
+	}
+}
+
+func TestHTTPGetBacksOffAndServesCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	var failing atomic.Bool
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("cached"))
+	}))
+	defer srv.Close()
+
+	c := newHTTPClient(HTTPConfig{})
+	if _, _, err := c.Get(context.Background(), "test", srv.URL, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	failing.Store(true)
+	body, _, err := c.Get(context.Background(), "test", srv.URL, 0)
+	if err == nil || string(body) != "cached" {
+		t.Fatalf("failed Get = %q, %v; want the cached body and an error", body, err)
+	}
+	body, _, err = c.Get(context.Background(), "test", srv.URL, 0)
+	if err == nil || string(body) != "cached" {
+		t.Fatalf("Get while backing off = %q, %v", body, err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d requests, want 2: the URL should be left alone after failing", n)
+	}
+}
@@ -3,19 +3,23 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
-	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 type HyprlandWorkspace struct {
 	ID              int    `json:"id"`
 	Name            string `json:"name"`
 	Monitor         string `json:"monitor"`
-	Windows         string `json:"windows"`
+	Windows         int    `json:"windows"`
 	HasFullscreen   bool   `json:"hasfullscreen"`
 	LastWindow      string `json:"lastwindow"`
 	LastWindowTitle string `json:"lastwindowtitle"`
@@ -29,12 +33,13 @@ type HyprlandWindow struct {
 		ID   int    `json:"id"`
 		Name string `json:"name"`
 	} `json:"workspace"`
-	Monitor    string `json:"monitor"`
-	Fullscreen bool   `json:"fullscreen"`
-	Floating   bool   `json:"floating"`
-	Pinned     bool   `json:"pinned"`
-	At         [2]int `json:"at"`
-	Size       [2]int `json:"size"`
+	Monitor    int            `json:"monitor"`
+	Fullscreen hyprFullscreen `json:"fullscreen"`
+	Floating   bool           `json:"floating"`
+	Pinned     bool           `json:"pinned"`
+	Pid        int            `json:"pid"`
+	At         [2]int         `json:"at"`
+	Size       [2]int         `json:"size"`
 }
 
 type HyprlandMonitor struct {
@@ -53,6 +58,10 @@ type HyprlandMonitor struct {
 		ID   int    `json:"id"`
 		Name string `json:"name"`
 	} `json:"activeWorkspace"`
+	SpecialWorkspace struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"specialWorkspace"`
 	Reserved   [4]int  `json:"reserved"`
 	Scale      float64 `json:"scale"`
 	Transform  int     `json:"transform"`
@@ -61,6 +70,14 @@ type HyprlandMonitor struct {
 	Vrr        bool    `json:"vrr"`
 }
 
+type HyprlandOption struct {
+	Option string  `json:"option"`
+	Int    int     `json:"int"`
+	Float  float64 `json:"float"`
+	Str    string  `json:"str"`
+	Set    bool    `json:"set"`
+}
+
 type HyprlandEvent struct {
 	Type string
 	Data []string
@@ -68,27 +85,40 @@ type HyprlandEvent struct {
 
 type HyprlandClient struct {
 	signature   string
+	socketDir   string
+	version     HyprlandVersion
 	commandConn net.Conn
 	eventConn   net.Conn
 	eventMux    sync.RWMutex
 	listeners   []chan HyprlandEvent
+	dropped     atomic.Uint64
+	listening   atomic.Bool
+	rawAllow    []string
 }
 
 func NewHyprlandClient() (*HyprlandClient, error) {
-	signature := os.Getenv("HYPRLAND_INSTANCE_SIGNATURE")
+	signature := hyprSignature()
 
 	if signature == "" {
 		return nil, fmt.Errorf("not running in hyprland")
 	}
 
-	return &HyprlandClient{
+	hc := &HyprlandClient{
 		listeners: make([]chan HyprlandEvent, 0),
 		signature: signature,
-	}, nil
+		socketDir: hyprSocketDir(signature),
+	}
+
+	version, err := hc.GetVersion()
+	if err != nil {
+		log.Printf("hyprland version check failed: %v", err)
+	}
+	hc.version = version
+	return hc, nil
 }
 
 func (hc *HyprlandClient) sendCommand(command string) ([]byte, error) {
-	socketPath := fmt.Sprintf("/tmp/hypr/%s/.socket.sock", hc.signature)
+	socketPath := filepath.Join(hc.socketDir, ".socket.sock")
 
 	conn, err := net.Dial("unix", socketPath)
 	if err != nil {
@@ -100,12 +130,7 @@ func (hc *HyprlandClient) sendCommand(command string) ([]byte, error) {
 		return nil, err
 	}
 
-	buf := make([]byte, 16384)
-	n, err := conn.Read(buf)
-	if err != nil {
-		return nil, err
-	}
-	return buf[:n], nil
+	return io.ReadAll(conn)
 }
 
 func (hc *HyprlandClient) GetActiveWorkspace() (*HyprlandWorkspace, error) {
@@ -186,24 +211,126 @@ func (hc *HyprlandClient) GetActiveMonitor() (*HyprlandMonitor, error) {
 	return nil, fmt.Errorf("no focused monitor found")
 }
 
+type HyprlandKeyboard struct {
+	Name         string `json:"name"`
+	ActiveKeymap string `json:"active_keymap"`
+	CapsLock     bool   `json:"capsLock"`
+	NumLock      bool   `json:"numLock"`
+	Main         bool   `json:"main"`
+}
+
+func (hc *HyprlandClient) GetKeyboards() ([]HyprlandKeyboard, error) {
+	data, err := hc.sendCommand("j/devices")
+	if err != nil {
+		return nil, err
+	}
+
+	var devices struct {
+		Keyboards []HyprlandKeyboard `json:"keyboards"`
+	}
+	if err := json.Unmarshal(data, &devices); err != nil {
+		return nil, err
+	}
+	return devices.Keyboards, nil
+}
+
+func (hc *HyprlandClient) GetCursorPos() (x, y int, err error) {
+	data, err := hc.sendCommand("j/cursorpos")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var pos struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return 0, 0, err
+	}
+	return pos.X, pos.Y, nil
+}
+
+func (hc *HyprlandClient) GetOption(name string) (*HyprlandOption, error) {
+	data, err := hc.sendCommand("j/getoption " + name)
+	if err != nil {
+		return nil, err
+	}
+
+	var option HyprlandOption
+	if err := json.Unmarshal(data, &option); err != nil {
+		return nil, err
+	}
+	return &option, nil
+}
+
+func (hc *HyprlandClient) SetKeyword(keyword, value string) error {
+	cmd := fmt.Sprintf("keyword %s %s", keyword, value)
+	_, err := hc.sendCommand(cmd)
+	return err
+}
+
+func (hc *HyprlandClient) GetLayout() (string, error) {
+	option, err := hc.GetOption("general:layout")
+	if err != nil {
+		return "", err
+	}
+	return option.Str, nil
+}
+
+func (hc *HyprlandClient) SetLayout(layout string) error {
+	return hc.SetKeyword("general:layout", layout)
+}
+
 func (hc *HyprlandClient) SwitchWorkspace(workspace int) error {
 	cmd := fmt.Sprintf("dispatch workspace %d", workspace)
 	_, err := hc.sendCommand(cmd)
 	return err
 }
 
+// SwitchWorkspacePrevious goes back to the workspace focused before the
+// current one.
+func (hc *HyprlandClient) SwitchWorkspacePrevious() error {
+	_, err := hc.sendCommand("dispatch workspace previous")
+	return err
+}
+
 func (hc *HyprlandClient) SwitchWorkspaceByName(name string) error {
-	cmd := fmt.Sprintf("dispatch workspace name %s", name)
+	cmd := fmt.Sprintf("dispatch workspace name:%s", name)
+	reply, err := hc.sendCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if r := strings.TrimSpace(string(reply)); r != "ok" {
+		return fmt.Errorf("hyprland: %s", r)
+	}
+	return nil
+}
+
+// SwitchWorkspaceRelative cycles through the existing workspaces on the
+// focused monitor.
+func (hc *HyprlandClient) SwitchWorkspaceRelative(offset int) error {
+	cmd := fmt.Sprintf("dispatch workspace e%+d", offset)
 	_, err := hc.sendCommand(cmd)
 	return err
 }
 
+func (hc *HyprlandClient) ToggleSpecialWorkspace(name string) error {
+	_, err := hc.sendCommand("dispatch togglespecialworkspace " + name)
+	return err
+}
+
 func (hc *HyprlandClient) MoveToWorkspace(workspace int) error {
 	cmd := fmt.Sprintf("dispatch movetoworkspace %d", workspace)
 	_, err := hc.sendCommand(cmd)
 	return err
 }
 
+func (hc *HyprlandClient) MoveToWorkspaceRelative(offset int) error {
+	cmd := fmt.Sprintf("dispatch movetoworkspace r%+d", offset)
+	_, err := hc.sendCommand(cmd)
+	return err
+}
+
 func (hc *HyprlandClient) ToggleFullscreen() error {
 	_, err := hc.sendCommand("dispatch fullscreen")
 	return err
@@ -219,6 +346,92 @@ func (hc *HyprlandClient) ToggleFloating() error {
 	return err
 }
 
+// AllowRaw sets the command prefixes Raw accepts, e.g. "dispatch workspace"
+// or "keyword general:layout". Nothing is allowed by default.
+func (hc *HyprlandClient) AllowRaw(prefixes []string) {
+	hc.rawAllow = prefixes
+}
+
+// Raw sends a hyprctl-style command over the command socket if it matches
+// the allowlist, returning the compositor's reply.
+func (hc *HyprlandClient) Raw(command string) (string, error) {
+	command = strings.TrimSpace(command)
+	switch {
+	case command == "":
+		return "", errors.New("empty command")
+	case strings.ContainsAny(command, "\n\x00"):
+		return "", errors.New("command contains control characters")
+	case strings.HasPrefix(command, "[[BATCH]]"):
+		return "", errors.New("batch commands are not allowed")
+	}
+
+	allowed := false
+	for _, prefix := range hc.rawAllow {
+		if command == prefix || strings.HasPrefix(command, prefix+" ") {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", fmt.Errorf("%q is not in hyprctl.allow", command)
+	}
+
+	reply, err := hc.sendCommand(command)
+	if err != nil {
+		return "", err
+	}
+	if r := strings.TrimSpace(string(reply)); r != "ok" && strings.HasPrefix(command, "dispatch ") {
+		return r, fmt.Errorf("hyprland: %s", r)
+	}
+	return string(reply), nil
+}
+
+// windowSelector addresses a specific window. Event payloads carry
+// addresses without the 0x prefix that j/clients reports, so accept both.
+func windowSelector(address string) string {
+	return "address:0x" + normalizeAddress(address)
+}
+
+// dispatchWindow runs a dispatcher against one window rather than the
+// active one; args, if any, go before the window selector.
+func (hc *HyprlandClient) dispatchWindow(dispatcher, args, address string) error {
+	target := windowSelector(address)
+	if args != "" {
+		target = args + "," + target
+	}
+	_, err := hc.sendCommand(fmt.Sprintf("dispatch %s %s", dispatcher, target))
+	return err
+}
+
+func (hc *HyprlandClient) FocusWindow(address string) error {
+	return hc.dispatchWindow("focuswindow", "", address)
+}
+
+func (hc *HyprlandClient) CloseWindow(address string) error {
+	return hc.dispatchWindow("closewindow", "", address)
+}
+
+func (hc *HyprlandClient) ToggleFloatingWindow(address string) error {
+	return hc.dispatchWindow("togglefloating", "", address)
+}
+
+func (hc *HyprlandClient) TogglePin(address string) error {
+	return hc.dispatchWindow("pin", "", address)
+}
+
+// MoveWindowToWorkspace moves the window without following it.
+func (hc *HyprlandClient) MoveWindowToWorkspace(address string, workspace int) error {
+	return hc.dispatchWindow("movetoworkspacesilent", strconv.Itoa(workspace), address)
+}
+
+func (hc *HyprlandClient) MoveWindowPixel(address string, x, y int) error {
+	return hc.dispatchWindow("movewindowpixel", fmt.Sprintf("exact %d %d", x, y), address)
+}
+
+func (hc *HyprlandClient) ResizeWindowPixel(address string, width, height int) error {
+	return hc.dispatchWindow("resizewindowpixel", fmt.Sprintf("exact %d %d", width, height), address)
+}
+
 func (hc *HyprlandClient) FocusMonitor(monitor string) error {
 	cmd := fmt.Sprintf("dispatch focusmonitor %s", monitor)
 	_, err := hc.sendCommand(cmd)
@@ -232,12 +445,13 @@ func (hc *HyprlandClient) MoveWorkspaceToMontior(workspace int, monitor string)
 }
 
 func (hc *HyprlandClient) StartEventListener() error {
-	socketPath := fmt.Sprintf("/tmp/hypr/%s/.socket2.sock", hc.signature)
+	socketPath := filepath.Join(hc.socketDir, ".socket2.sock")
 	conn, err := net.Dial("unix", socketPath)
 	if err != nil {
 		return fmt.Errorf("failed to connect to event socket: %v", err)
 	}
 	hc.eventConn = conn
+	hc.listening.Store(true)
 
 	go hc.readEvents()
 	log.Println("Connected to Hyprland event socket")
@@ -246,6 +460,7 @@ func (hc *HyprlandClient) StartEventListener() error {
 
 func (hc *HyprlandClient) readEvents() {
 	defer hc.eventConn.Close()
+	defer hc.listening.Store(false)
 
 	scanner := bufio.NewScanner(hc.eventConn)
 	for scanner.Scan() {
@@ -256,7 +471,7 @@ func (hc *HyprlandClient) readEvents() {
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
 		log.Printf("Error reading from event socket: %v", err)
 	}
 }
@@ -268,7 +483,12 @@ func (hc *HyprlandClient) parseEvent(line string) *HyprlandEvent {
 	}
 
 	eventType := parts[0]
-	eventData := strings.Split(parts[1], ",")
+	var eventData []string
+	if arity, ok := hyprEventArity[eventType]; ok {
+		eventData = strings.SplitN(parts[1], ",", arity)
+	} else {
+		eventData = strings.Split(parts[1], ",")
+	}
 
 	return &HyprlandEvent{
 		Type: eventType,
@@ -284,10 +504,22 @@ func (hc *HyprlandClient) dispatchEvent(event HyprlandEvent) {
 		select {
 		case listener <- event:
 		default:
+			hc.dropped.Add(1)
 		}
 	}
 }
 
+// DroppedEvents counts events discarded because a subscriber's buffer was full.
+func (hc *HyprlandClient) DroppedEvents() uint64 {
+	return hc.dropped.Load()
+}
+
+// Listening reports whether socket2 is connected, i.e. whether state
+// changes will arrive as events rather than having to be polled for.
+func (hc *HyprlandClient) Listening() bool {
+	return hc != nil && hc.listening.Load()
+}
+
 func (hc *HyprlandClient) Subscribe() chan HyprlandEvent {
 	hc.eventMux.Lock()
 	defer hc.eventMux.Unlock()
@@ -323,9 +555,8 @@ func (hc *HyprlandClient) Close() {
 }
 
 // helpers
-func getActiveWorkspace() int {
-	client, err := NewHyprlandClient()
-	if err != nil {
+func getActiveWorkspace(client *HyprlandClient) int {
+	if client == nil {
 		return 1
 	}
 	ws, err := client.GetActiveWorkspace()
@@ -335,9 +566,8 @@ func getActiveWorkspace() int {
 	return ws.ID
 }
 
-func getActiveWindow() string {
-	client, err := NewHyprlandClient()
-	if err != nil {
+func getActiveWindow(client *HyprlandClient) string {
+	if client == nil {
 		return ""
 	}
 
@@ -386,3 +616,14 @@ func (hc *HyprlandClient) GetWorkspaceByName(name string) (*HyprlandWorkspace, e
 	}
 	return nil, fmt.Errorf("workspace not found: %s", name)
 }
+
+var hyprLayouts = []string{"dwindle", "master"}
+
+func nextLayout(current string) string {
+	for i, layout := range hyprLayouts {
+		if layout == current {
+			return hyprLayouts[(i+1)%len(hyprLayouts)]
+		}
+	}
+	return hyprLayouts[0]
+}
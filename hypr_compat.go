@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+type HyprlandVersion struct {
+	Major int
+	Minor int
+	Patch int
+	Raw   string
+}
+
+// hyprMaxKnown is the newest release the events and payloads were checked
+// against; any patch release of it counts as known.
+var (
+	hyprMinSupported = HyprlandVersion{Major: 0, Minor: 30}
+	hyprMaxKnown     = HyprlandVersion{Major: 0, Minor: 50}
+)
+
+// first version emitting the v2 variant of an event; the v2 data only adds
+// fields (ids) to the legacy ones
+var hyprV2Events = map[string]HyprlandVersion{
+	"workspace":        {Major: 0, Minor: 38},
+	"createworkspace":  {Major: 0, Minor: 38},
+	"destroyworkspace": {Major: 0, Minor: 38},
+	"moveworkspace":    {Major: 0, Minor: 38},
+	"movewindow":       {Major: 0, Minor: 38},
+	"windowtitle":      {Major: 0, Minor: 42},
+}
+
+// number of comma separated fields per event, the last one keeps any
+// remaining commas (window titles, workspace names)
+var hyprEventArity = map[string]int{
+	"workspace":          1,
+	"workspacev2":        2,
+	"focusedmon":         2,
+	"activewindow":       2,
+	"activewindowv2":     1,
+	"fullscreen":         1,
+	"monitorremoved":     1,
+	"monitoradded":       1,
+	"createworkspace":    1,
+	"createworkspacev2":  2,
+	"destroyworkspace":   1,
+	"destroyworkspacev2": 2,
+	"moveworkspace":      2,
+	"moveworkspacev2":    3,
+	"renameworkspace":    2,
+	"activespecial":      2,
+	"activelayout":       2,
+	"openwindow":         4,
+	"closewindow":        1,
+	"movewindow":         2,
+	"movewindowv2":       3,
+	"windowtitle":        1,
+	"windowtitlev2":      2,
+	"urgent":             1,
+	"screencast":         2,
+}
+
+func parseHyprlandVersion(s string) (HyprlandVersion, bool) {
+	v := HyprlandVersion{Raw: s}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "-+ "); i >= 0 {
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return v, false
+	}
+	nums := make([]int, 3)
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return v, false
+		}
+		nums[i] = n
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, true
+}
+
+func (v HyprlandVersion) compare(o HyprlandVersion) int {
+	switch {
+	case v.Major != o.Major:
+		return v.Major - o.Major
+	case v.Minor != o.Minor:
+		return v.Minor - o.Minor
+	}
+	return v.Patch - o.Patch
+}
+
+func (v HyprlandVersion) AtLeast(o HyprlandVersion) bool {
+	return v.compare(o) >= 0
+}
+
+func (v HyprlandVersion) Known() bool {
+	return v.AtLeast(hyprMinSupported) && hyprMaxKnown.AtLeast(HyprlandVersion{Major: v.Major, Minor: v.Minor})
+}
+
+func (v HyprlandVersion) String() string {
+	if v.Major == 0 && v.Minor == 0 && v.Patch == 0 {
+		if v.Raw != "" {
+			return v.Raw
+		}
+		return "unknown"
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func (hc *HyprlandClient) GetVersion() (HyprlandVersion, error) {
+	data, err := hc.sendCommand("j/version")
+	if err != nil {
+		return HyprlandVersion{}, err
+	}
+
+	var info struct {
+		Version string `json:"version"`
+		Tag     string `json:"tag"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return HyprlandVersion{}, err
+	}
+
+	for _, raw := range []string{info.Version, info.Tag} {
+		if v, ok := parseHyprlandVersion(raw); ok {
+			return v, nil
+		}
+	}
+	return HyprlandVersion{Raw: info.Tag}, fmt.Errorf("unrecognized hyprland version %q", info.Tag)
+}
+
+func (hc *HyprlandClient) Version() HyprlandVersion {
+	return hc.version
+}
+
+// eventName maps a base event to the variant emitted by the running compositor
+func (hc *HyprlandClient) eventName(base string) string {
+	if since, ok := hyprV2Events[base]; ok && hc.version.AtLeast(since) {
+		return base + "v2"
+	}
+	return base
+}
+
+// normalizeEvent gives modules one event per change under its base name.
+// Compositors with a v2 variant send both; the legacy one is dropped and
+// the v2 one renamed, so handlers tell the payloads apart by field count.
+func (hc *HyprlandClient) normalizeEvent(event HyprlandEvent) (HyprlandEvent, bool) {
+	if base, ok := strings.CutSuffix(event.Type, "v2"); ok {
+		if _, ok := hyprV2Events[base]; ok {
+			event.Type = base
+		}
+		return event, true
+	}
+	return event, hc.eventName(event.Type) == event.Type
+}
+
+// hyprSignature picks the Hyprland instance to talk to. With several
+// sessions (another seat, or a second login of the same user) the
+// inherited HYPRLAND_INSTANCE_SIGNATURE can belong to a different one, so
+// when more than one instance is running the one in this process's
+// logind session wins. It's worked out once.
+var hyprSignature = sync.OnceValue(func() string {
+	signature := os.Getenv("HYPRLAND_INSTANCE_SIGNATURE")
+	runtime := os.Getenv("XDG_RUNTIME_DIR")
+	if runtime == "" {
+		return signature
+	}
+	instances, err := os.ReadDir(filepath.Join(runtime, "hypr"))
+	if err != nil || len(instances) < 2 {
+		return signature
+	}
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return signature
+	}
+	session := currentSessionID(conn)
+	if session == "" {
+		return signature
+	}
+
+	for _, instance := range instances {
+		// hyprland.lock holds the compositor's pid, then its wayland socket
+		data, err := os.ReadFile(filepath.Join(runtime, "hypr", instance.Name(), "hyprland.lock"))
+		if err != nil {
+			continue
+		}
+		line, _, _ := strings.Cut(string(data), "\n")
+		pid, err := strconv.Atoi(strings.TrimSpace(line))
+		if err != nil || sessionOfPID(conn, pid) != session {
+			continue
+		}
+		if instance.Name() != signature {
+			log.Printf("using hyprland instance %s from session %s instead of %q", instance.Name(), session, signature)
+		}
+		return instance.Name()
+	}
+	return signature
+})
+
+func hyprSocketDir(signature string) string {
+	if runtime := os.Getenv("XDG_RUNTIME_DIR"); runtime != "" {
+		dir := filepath.Join(runtime, "hypr", signature)
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+	}
+	return filepath.Join("/tmp", "hypr", signature)
+}
+
+// clients report fullscreen as a bool before 0.42 and as a mode number after
+type hyprFullscreen bool
+
+func (f *hyprFullscreen) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*f = hyprFullscreen(b)
+		return nil
+	}
+	var mode int
+	if err := json.Unmarshal(data, &mode); err != nil {
+		return err
+	}
+	*f = mode != 0
+	return nil
+}
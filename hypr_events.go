@@ -5,11 +5,15 @@ import (
 	"sync"
 )
 
+// HyprlandEventHandler is the bar's one subscriber to socket2: it
+// normalises each event, runs the callbacks registered for it and passes
+// it on to the model through Events.
 type HyprlandEventHandler struct {
 	client    *HyprlandClient
 	callbacks map[string][]EventCallback
 	mu        sync.RWMutex
 	events    chan HyprlandEvent
+	out       chan HyprlandEvent
 	stopChan  chan struct{}
 }
 
@@ -19,6 +23,7 @@ func NewHyprlandEventHandler(client *HyprlandClient) *HyprlandEventHandler {
 	return &HyprlandEventHandler{
 		client:    client,
 		callbacks: make(map[string][]EventCallback),
+		out:       make(chan HyprlandEvent, 100),
 		stopChan:  make(chan struct{}),
 	}
 }
@@ -38,16 +43,28 @@ func (h *HyprlandEventHandler) Start() error {
 	return nil
 }
 
+// Events delivers every normalised event; it is closed when the handler
+// stops or the socket goes away.
+func (h *HyprlandEventHandler) Events() <-chan HyprlandEvent {
+	return h.out
+}
+
 func (h *HyprlandEventHandler) Stop() {
 	close(h.stopChan)
 	h.client.Unsubscribe(h.events)
 }
 
 func (h *HyprlandEventHandler) handleEvents() {
+	defer close(h.out)
 	for {
 		select {
-		case event := <-h.events:
-			h.processEvent(event)
+		case event, ok := <-h.events:
+			if !ok {
+				return
+			}
+			if event, ok := h.client.normalizeEvent(event); ok {
+				h.processEvent(event)
+			}
 
 		case <-h.stopChan:
 			return
@@ -63,6 +80,12 @@ func (h *HyprlandEventHandler) processEvent(event HyprlandEvent) {
 	for _, callback := range callbacks {
 		go callback(event)
 	}
+
+	select {
+	case h.out <- event:
+	default:
+		h.client.dropped.Add(1)
+	}
 }
 
 // typed event handlers
@@ -74,16 +97,26 @@ type WindowCloseCallback func(address string)
 
 func (h *HyprlandEventHandler) OnWorkspaceChange(callback WorkspaceCallback) {
 	h.On("workspace", func(event HyprlandEvent) {
-		if len(event.Data) > 0 {
-			if id, err := strconv.Atoi(event.Data[0]); err == nil {
-				callback(id, event.Data[0])
-			} else {
-				callback(0, event.Data[0])
-			}
+		id, name, ok := workspaceFromEvent(event)
+		if ok {
+			callback(id, name)
 		}
 	})
 }
 
+// workspace events carry "NAME" before v2 and "ID,NAME" after
+func workspaceFromEvent(event HyprlandEvent) (int, string, bool) {
+	switch {
+	case len(event.Data) >= 2:
+		id, _ := strconv.Atoi(event.Data[0])
+		return id, event.Data[1], true
+	case len(event.Data) > 0:
+		id, _ := strconv.Atoi(event.Data[0])
+		return id, event.Data[0], true
+	}
+	return 0, "", false
+}
+
 func (h *HyprlandEventHandler) OnActiveWindow(callback WindowCallback) {
 	h.On("activewindow", func(event HyprlandEvent) {
 		if len(event.Data) >= 2 {
@@ -119,23 +152,23 @@ func (h *HyprlandEventHandler) OnWindowClose(callback WindowCloseCallback) {
 func (h *HyprlandEventHandler) OnFullscreenToggle(callback func(fullscreen bool)) {
 	h.On("fullscreen", func(event HyprlandEvent) {
 		if len(event.Data) > 0 {
-			callback(event.Data[0] == "1")
+			callback(event.Data[0] != "0")
 		}
 	})
 }
 
 func (h *HyprlandEventHandler) OnWorkspaceCreate(callback func(workspaceName string)) {
 	h.On("createworkspace", func(event HyprlandEvent) {
-		if len(event.Data) > 0 {
-			callback(event.Data[0])
+		if _, name, ok := workspaceFromEvent(event); ok {
+			callback(name)
 		}
 	})
 }
 
 func (h *HyprlandEventHandler) OnWorkspaceDestroy(callback func(workdspaceName string)) {
 	h.On("destroyworkspace", func(event HyprlandEvent) {
-		if len(event.Data) > 0 {
-			callback(event.Data[0])
+		if _, name, ok := workspaceFromEvent(event); ok {
+			callback(name)
 		}
 	})
 }
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// barWindowRules turn a terminal with class "tui-bar" into a bar docked
+// at the top of monitor (any monitor when empty).
+func barWindowRules(monitor string, height int) []string {
+	rules := []string{
+		"float",
+		"pin",
+		"move 0 0",
+		fmt.Sprintf("size 100%% %d", height),
+		"noborder",
+		"noshadow",
+		"rounding 0",
+		"noanim",
+		"nofocus",
+	}
+	if monitor != "" {
+		rules = append(rules, "monitor "+monitor)
+	}
+	for i, rule := range rules {
+		rules[i] = rule + ", class:^(tui-bar)$"
+	}
+	return rules
+}
+
+func reservedArea(monitor string, height int) string {
+	return fmt.Sprintf("%s, addreserved, %d, 0, 0, 0", monitor, height)
+}
+
+func hyprlandSnippet(monitor string, height int) string {
+	var b strings.Builder
+	b.WriteString("# tui-bar\n")
+	if monitor != "" {
+		fmt.Fprintf(&b, "monitor = %s\n", reservedArea(monitor, height))
+	}
+	for _, rule := range barWindowRules(monitor, height) {
+		fmt.Fprintf(&b, "windowrulev2 = %s\n", rule)
+	}
+	return b.String()
+}
+
+func applyHyprlandRules(hc *HyprlandClient, rules []string) error {
+	for _, rule := range rules {
+		if err := hc.SetKeyword("windowrulev2", rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runSetupHyprland prints or writes the config needed to dock the bar
+// terminal at the top of a monitor, and can reserve the space live.
+func runSetupHyprland(args []string) int {
+	fs := flag.NewFlagSet("setup-hyprland", flag.ExitOnError)
+	monitor := fs.String("monitor", "", "monitor to dock on (default: focused monitor)")
+	height := fs.Int("height", 32, "bar height in pixels")
+	write := fs.Bool("write", false, "write the snippet to ~/.config/hypr/tui-bar.conf")
+	apply := fs.Bool("apply", false, "reserve the space and apply the rules on the running Hyprland")
+	fs.Parse(args)
+
+	hc, hyprErr := NewHyprlandClient()
+	if *monitor == "" && hc != nil {
+		if mon, err := hc.GetActiveMonitor(); err == nil {
+			*monitor = mon.Name
+		}
+	}
+	if *monitor == "" {
+		fmt.Println("Err: no monitor given and none focused, pass --monitor")
+		return 1
+	}
+	snippet := hyprlandSnippet(*monitor, *height)
+
+	if *write {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			fmt.Printf("Err: %v\n", err)
+			return 1
+		}
+		path := filepath.Join(configDir, "hypr", "tui-bar.conf")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			fmt.Printf("Err: %v\n", err)
+			return 1
+		}
+		if err := os.WriteFile(path, []byte(snippet), 0o644); err != nil {
+			fmt.Printf("Err: %v\n", err)
+			return 1
+		}
+		fmt.Printf("wrote %s\nadd to hyprland.conf: source = %s\n", path, path)
+	} else {
+		fmt.Print(snippet)
+	}
+
+	if *apply {
+		if hc == nil {
+			fmt.Printf("Err: %v\n", hyprErr)
+			return 1
+		}
+		if err := hc.SetKeyword("monitor", reservedArea(*monitor, *height)); err != nil {
+			fmt.Printf("Err: failed to reserve space: %v\n", err)
+			return 1
+		}
+		if err := applyHyprlandRules(hc, barWindowRules(*monitor, *height)); err != nil {
+			fmt.Printf("Err: failed to apply window rules: %v\n", err)
+			return 1
+		}
+		fmt.Println("applied to the running Hyprland")
+	}
+	return 0
+}
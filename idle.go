@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type LockConfig struct {
+	Command     []string `json:"command"`
+	IdleTimeout int      `json:"idle_timeout"`
+	WarnSeconds int      `json:"warn_seconds"`
+}
+
+// idleLockTimeout finds how long the session may idle before the idle
+// daemon locks it: the configured value, hypridle's listener whose
+// on-timeout locks, or a running swayidle's "timeout N <lock>" argument.
+func idleLockTimeout(configured int) time.Duration {
+	if configured > 0 {
+		return time.Duration(configured) * time.Second
+	}
+	if t := hypridleLockTimeout(); t > 0 {
+		return t
+	}
+	return swayidleLockTimeout()
+}
+
+func hypridleLockTimeout() time.Duration {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return 0
+	}
+	f, err := os.Open(filepath.Join(configDir, "hypr", "hypridle.conf"))
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	var timeout int
+	var best time.Duration
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, ok := strings.Cut(line, "=")
+		if strings.HasPrefix(line, "listener") {
+			timeout = 0
+			continue
+		}
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "timeout":
+			timeout, _ = strconv.Atoi(value)
+		case "on-timeout":
+			if strings.Contains(value, "lock") && timeout > 0 {
+				t := time.Duration(timeout) * time.Second
+				if best == 0 || t < best {
+					best = t
+				}
+			}
+		}
+	}
+	return best
+}
+
+func swayidleLockTimeout() time.Duration {
+	_, args, ok := findProcess("swayidle")
+	if !ok {
+		return 0
+	}
+	for i := 0; i+2 < len(args); i++ {
+		if args[i] != "timeout" || !strings.Contains(args[i+2], "lock") {
+			continue
+		}
+		if seconds, err := strconv.Atoi(args[i+1]); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return 0
+}
+
+type cursorMsg struct{ x, y int }
+
+// activity approximates user input: Wayland gives clients no idle time,
+// so it is inferred from cursor movement and compositor events; typing in
+// a single window without touching the mouse is not seen.
+type activity struct {
+	cursor cursorMsg
+	last   time.Time
+}
+
+func newActivity() activity {
+	return activity{last: time.Now()}
+}
+
+func (a *activity) observe(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case cursorMsg:
+		if msg != a.cursor {
+			a.cursor = msg
+			a.last = time.Now()
+		}
+	case hyprEventMsg:
+		a.last = time.Now()
+	}
+}
+
+func (a *activity) idle() time.Duration {
+	return time.Since(a.last)
+}
+
+func fetchCursor(id string, hc *HyprlandClient) tea.Cmd {
+	if hc == nil {
+		return nil
+	}
+	return moduleCmd(id, func() tea.Msg {
+		x, y, err := hc.GetCursorPos()
+		if err != nil {
+			return nil
+		}
+		return cursorMsg{x, y}
+	})
+}
+
+// tickElapsed moves last on to now and returns the time in between, or
+// false when it doesn't count: a suspend or a stalled loop is not screen
+// time.
+func tickElapsed(last *time.Time, now time.Time) (time.Duration, bool) {
+	elapsed := now.Sub(*last)
+	*last = now
+	return elapsed, elapsed > 0 && elapsed <= 10*time.Second
+}
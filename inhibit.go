@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// logind inhibitor locks: held for as long as the returned fd stays open
+
+func inhibitIdle(why string) (*os.File, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	var fd dbus.UnixFD
+	err = conn.Object("org.freedesktop.login1", "/org/freedesktop/login1").
+		Call("org.freedesktop.login1.Manager.Inhibit", 0, "idle:sleep", "tui-bar", why, "block").
+		Store(&fd)
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(fd), "inhibit"), nil
+}
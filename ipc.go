@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type ipcMsg struct {
+	command string
+	args    []string
+	reply   chan string
+}
+
+type IPCServer struct {
+	listener net.Listener
+	requests chan ipcMsg
+	done     chan struct{}
+	once     sync.Once
+}
+
+func ipcSocketPath() string {
+	if runtime := os.Getenv("XDG_RUNTIME_DIR"); runtime != "" {
+		return filepath.Join(runtime, "tui-bar.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("tui-bar-%d.sock", os.Getuid()))
+}
+
+func NewIPCServer() (*IPCServer, error) {
+	path := ipcSocketPath()
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another bar is listening on %s", path)
+	}
+	os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %v", path, err)
+	}
+
+	s := &IPCServer{
+		listener: listener,
+		requests: make(chan ipcMsg),
+		done:     make(chan struct{}),
+	}
+	go s.accept()
+	return s, nil
+}
+
+func (s *IPCServer) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *IPCServer) handle(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		return
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		fmt.Fprintln(conn, "error: empty command")
+		return
+	}
+
+	req := ipcMsg{
+		command: fields[0],
+		args:    fields[1:],
+		reply:   make(chan string, 1),
+	}
+	select {
+	case s.requests <- req:
+	case <-s.done:
+		return
+	}
+
+	select {
+	case reply := <-req.reply:
+		fmt.Fprintln(conn, reply)
+	case <-time.After(2 * time.Second):
+		fmt.Fprintln(conn, "error: bar did not respond")
+	}
+}
+
+func (s *IPCServer) Close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.listener.Close()
+		os.Remove(ipcSocketPath())
+	})
+}
+
+func waitForIPC(s *IPCServer) tea.Cmd {
+	if s == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		select {
+		case req := <-s.requests:
+			return req
+		case <-s.done:
+			return nil
+		}
+	}
+}
+
+func sendIPCCommand(args []string) (string, error) {
+	conn, err := net.Dial("unix", ipcSocketPath())
+	if err != nil {
+		return "", fmt.Errorf("bar is not running: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := fmt.Fprintln(conn, strings.Join(args, " ")); err != nil {
+		return "", err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && reply == "" {
+		return "", err
+	}
+	return strings.TrimSpace(reply), nil
+}
+
+func runMsgCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Println("usage: tui-bar msg <command> [args...]")
+		return 2
+	}
+	reply, err := sendIPCCommand(args)
+	if err != nil {
+		fmt.Printf("Err: %v\n", err)
+		return 1
+	}
+	fmt.Println(reply)
+	if strings.HasPrefix(reply, "error") {
+		return 1
+	}
+	return 0
+}
+
+func (m model) handleIPC(msg ipcMsg) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	reply := "ok"
+
+	switch msg.command {
+	case "hide":
+		m, cmd = m.setHidden(true)
+	case "show":
+		m, cmd = m.setHidden(false)
+	case "toggle":
+		m, cmd = m.setHidden(!m.hidden)
+	case "debug":
+		cmd = m.toggleDebug()
+	case "present":
+		switch {
+		case len(msg.args) == 0 || msg.args[0] == "toggle":
+			m.present = !m.present
+		case msg.args[0] == "on" || msg.args[0] == "off":
+			m.present = msg.args[0] == "on"
+		default:
+			reply = "error: usage: present [on|off|toggle]"
+		}
+	case "focus":
+		switch {
+		case len(msg.args) == 0 || msg.args[0] == "toggle":
+			m, cmd = m.setFocus(!m.focus)
+		case msg.args[0] == "on" || msg.args[0] == "off":
+			m, cmd = m.setFocus(msg.args[0] == "on")
+		default:
+			reply = "error: usage: focus [on|off|toggle]"
+		}
+	case "popup":
+		if len(msg.args) > 0 {
+			m, cmd = m.setPopup(msg.args[0])
+		}
+	case "previous-workspace":
+		if m.hypr != nil {
+			cmd = hyprActionCmd(m.hypr, m.hypr.SwitchWorkspacePrevious, nil)
+		}
+	case "metrics":
+		reply = m.metricsReply(msg.args)
+	case "theme":
+		m, reply = m.themeReply(msg.args)
+	case "stats":
+		reply = m.statsReply(msg.args)
+	default:
+		reply = fmt.Sprintf("error: unknown command %q", msg.command)
+	}
+
+	msg.reply <- reply
+	return m, tea.Batch(cmd, waitForIPC(m.ipc))
+}
+
+// exporter is implemented by modules whose data `tui-bar msg stats <id>`
+// can dump as JSON.
+type exporter interface {
+	Export() any
+}
+
+func (m model) statsReply(args []string) string {
+	if len(args) == 0 {
+		return "error: usage: stats <module>"
+	}
+	e, ok := m.moduleByID[args[0]].(exporter)
+	if !ok {
+		return fmt.Sprintf("error: %s has no stats", args[0])
+	}
+	data, err := json.Marshal(e.Export())
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return string(data)
+}
+
+func startIPCServer() *IPCServer {
+	server, err := NewIPCServer()
+	if err != nil {
+		log.Printf("ipc disabled: %v", err)
+		return nil
+	}
+	return server
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/user"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Just enough IPP to list and cancel CUPS jobs, spoken over the local
+// socket (or CUPS_SERVER) so there's no need for the cups client library.
+
+const (
+	ippGetJobs   = 0x000a
+	ippCancelJob = 0x0008
+
+	ippTagOperation = 0x01
+	ippTagJob       = 0x02
+	ippTagEnd       = 0x03
+
+	ippInteger  = 0x21
+	ippEnum     = 0x23
+	ippName     = 0x42
+	ippKeyword  = 0x44
+	ippURI      = 0x45
+	ippCharset  = 0x47
+	ippLanguage = 0x48
+)
+
+type printJob struct {
+	id      int
+	name    string
+	user    string
+	printer string
+	state   int // 3 pending, 4 held, 5 processing, 6 stopped
+	created time.Time
+	reasons []string
+}
+
+// stuck reports jobs that won't finish by themselves: stopped or held
+// ones, and anything sitting in the queue for longer than ten minutes.
+func (j printJob) stuck(now time.Time) bool {
+	return j.state == 4 || j.state == 6 || !j.created.IsZero() && now.Sub(j.created) > 10*time.Minute
+}
+
+func (j printJob) stateName() string {
+	switch j.state {
+	case 3:
+		return "pending"
+	case 4:
+		return "held"
+	case 5:
+		return "printing"
+	case 6:
+		return "stopped"
+	}
+	return "job " + strconv.Itoa(j.state)
+}
+
+type ippAttribute struct {
+	tag   byte
+	name  string
+	value []byte
+}
+
+type ippRequest struct {
+	buf bytes.Buffer
+}
+
+func newIPPRequest(operation uint16) *ippRequest {
+	r := &ippRequest{}
+	binary.Write(&r.buf, binary.BigEndian, uint16(0x0101)) // IPP/1.1
+	binary.Write(&r.buf, binary.BigEndian, operation)
+	binary.Write(&r.buf, binary.BigEndian, uint32(1))
+	r.buf.WriteByte(ippTagOperation)
+	r.add(ippCharset, "attributes-charset", "utf-8")
+	r.add(ippLanguage, "attributes-natural-language", "en")
+	return r
+}
+
+// add writes an attribute; further values go in with an empty name.
+func (r *ippRequest) add(tag byte, name string, values ...string) {
+	for i, v := range values {
+		if i > 0 {
+			name = ""
+		}
+		r.buf.WriteByte(tag)
+		binary.Write(&r.buf, binary.BigEndian, uint16(len(name)))
+		r.buf.WriteString(name)
+		binary.Write(&r.buf, binary.BigEndian, uint16(len(v)))
+		r.buf.WriteString(v)
+	}
+}
+
+func (r *ippRequest) bytes() []byte {
+	r.buf.WriteByte(ippTagEnd)
+	return r.buf.Bytes()
+}
+
+// cupsClient posts to the scheduler's local socket, or to CUPS_SERVER when
+// that names a host or another socket.
+func cupsClient() (*http.Client, string) {
+	server := os.Getenv("CUPS_SERVER")
+	if server == "" {
+		server = "/run/cups/cups.sock"
+	}
+	if !strings.HasPrefix(server, "/") {
+		if !strings.Contains(server, ":") {
+			server += ":631"
+		}
+		return &http.Client{Timeout: 5 * time.Second}, "http://" + server + "/"
+	}
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", server)
+			},
+		},
+	}, "http://localhost/"
+}
+
+// ippCall sends a request and returns the attribute groups of the reply.
+func ippCall(req *ippRequest, endpoint string) ([][]ippAttribute, error) {
+	client, base := cupsClient()
+	resp, err := client.Post(base+strings.TrimPrefix(endpoint, "/"), "application/ipp", bytes.NewReader(req.bytes()))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cups: %s", resp.Status)
+	}
+	return parseIPPResponse(bufio.NewReader(resp.Body))
+}
+
+func parseIPPResponse(r *bufio.Reader) ([][]ippAttribute, error) {
+	var header struct {
+		Version   uint16
+		Status    uint16
+		RequestID uint32
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, err
+	}
+	// successful-ok and its -ignored-or-substituted variants
+	if header.Status > 0x00ff {
+		return nil, fmt.Errorf("ipp status %#04x", header.Status)
+	}
+
+	var groups [][]ippAttribute
+	var current []ippAttribute
+	name := ""
+	for {
+		tag, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if tag == ippTagEnd {
+			break
+		}
+		if tag < 0x10 {
+			// a delimiter starts the next group
+			if current != nil {
+				groups = append(groups, current)
+			}
+			current = []ippAttribute{{tag: tag}}
+			continue
+		}
+		field := func() ([]byte, error) {
+			var n uint16
+			if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+				return nil, err
+			}
+			b := make([]byte, n)
+			_, err := io.ReadFull(r, b)
+			return b, err
+		}
+		n, err := field()
+		if err != nil {
+			return nil, err
+		}
+		value, err := field()
+		if err != nil {
+			return nil, err
+		}
+		if len(n) > 0 {
+			name = string(n)
+		}
+		current = append(current, ippAttribute{tag: tag, name: name, value: value})
+	}
+	if current != nil {
+		groups = append(groups, current)
+	}
+	return groups, nil
+}
+
+func requestingUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// fetchPrintJobs lists every printer's unfinished jobs.
+func fetchPrintJobs() ([]printJob, error) {
+	req := newIPPRequest(ippGetJobs)
+	req.add(ippURI, "printer-uri", "ipp://localhost/")
+	req.add(ippName, "requesting-user-name", requestingUser())
+	req.add(ippKeyword, "which-jobs", "not-completed")
+	req.add(ippKeyword, "requested-attributes", "job-id", "job-name", "job-state", "job-state-reasons",
+		"job-originating-user-name", "job-printer-uri", "time-at-creation")
+	groups, err := ippCall(req, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []printJob
+	for _, group := range groups {
+		if group[0].tag != ippTagJob {
+			continue
+		}
+		var job printJob
+		for _, a := range group[1:] {
+			switch a.name {
+			case "job-id":
+				job.id = ippInt(a)
+			case "job-name":
+				job.name = string(a.value)
+			case "job-state":
+				job.state = ippInt(a)
+			case "job-state-reasons":
+				job.reasons = append(job.reasons, string(a.value))
+			case "job-originating-user-name":
+				job.user = string(a.value)
+			case "job-printer-uri":
+				job.printer = path.Base(string(a.value))
+			case "time-at-creation":
+				job.created = time.Unix(int64(ippInt(a)), 0)
+			}
+		}
+		if job.id > 0 {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func ippInt(a ippAttribute) int {
+	if (a.tag != ippInteger && a.tag != ippEnum) || len(a.value) != 4 {
+		return 0
+	}
+	return int(int32(binary.BigEndian.Uint32(a.value)))
+}
+
+func cancelPrintJob(id int) error {
+	req := newIPPRequest(ippCancelJob)
+	req.add(ippURI, "job-uri", fmt.Sprintf("ipp://localhost/jobs/%d", id))
+	req.add(ippName, "requesting-user-name", requestingUser())
+	_, err := ippCall(req, "/jobs/")
+	return err
+}
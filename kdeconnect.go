@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// KDE Connect's daemon on the session bus; each device and each of its
+// plugins is an object under /modules/kdeconnect/devices/<id>
+
+const (
+	kdeconnectService = "org.kde.kdeconnect"
+	kdeconnectPath    = "/modules/kdeconnect"
+	kdeconnectDevice  = "org.kde.kdeconnect.device"
+)
+
+type phoneState struct {
+	id            string
+	name          string
+	charge        int // -1 without the battery plugin
+	charging      bool
+	notifications int
+}
+
+func kdeconnectObject(conn *dbus.Conn, id, plugin string) dbus.BusObject {
+	path := kdeconnectPath + "/devices/" + id
+	if plugin != "" {
+		path += "/" + plugin
+	}
+	return conn.Object(kdeconnectService, dbus.ObjectPath(path))
+}
+
+// fetchPhone finds the reachable paired device called want (by name or
+// id), or the first one when want is empty.
+func fetchPhone(want string) (phoneState, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return phoneState{}, err
+	}
+	var ids []string
+	if err := conn.Object(kdeconnectService, kdeconnectPath).Call(kdeconnectService+".daemon.devices", 0, true, true).Store(&ids); err != nil {
+		return phoneState{}, err
+	}
+
+	for _, id := range ids {
+		phone := phoneState{id: id, charge: -1}
+		if v, err := kdeconnectObject(conn, id, "").GetProperty(kdeconnectDevice + ".name"); err == nil {
+			phone.name, _ = v.Value().(string)
+		}
+		if want != "" && want != id && !strings.EqualFold(want, phone.name) {
+			continue
+		}
+
+		battery := kdeconnectObject(conn, id, "battery")
+		if v, err := battery.GetProperty(kdeconnectDevice + ".battery.charge"); err == nil {
+			if charge, ok := v.Value().(int32); ok {
+				phone.charge = int(charge)
+			}
+		}
+		if v, err := battery.GetProperty(kdeconnectDevice + ".battery.isCharging"); err == nil {
+			phone.charging, _ = v.Value().(bool)
+		}
+		var active []string
+		if err := kdeconnectObject(conn, id, "notifications").Call(kdeconnectDevice+".notifications.activeNotifications", 0).Store(&active); err == nil {
+			phone.notifications = len(active)
+		}
+		return phone, nil
+	}
+	return phoneState{}, errors.New("no reachable device")
+}
+
+func ringPhone(id string) error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+	return kdeconnectObject(conn, id, "findmyphone").Call(kdeconnectDevice+".findmyphone.ring", 0).Err
+}
+
+func sendClipboardToPhone(id string) error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+	return kdeconnectObject(conn, id, "clipboard").Call(kdeconnectDevice+".clipboard.sendClipboard", 0).Err
+}
@@ -0,0 +1,48 @@
+package main
+
+import "path/filepath"
+
+type KeyboardConfig struct {
+	ShowNumLock bool `json:"show_num_lock"`
+}
+
+type lockKeys struct {
+	caps bool
+	num  bool
+}
+
+// fetchLockKeys reads lock state from Hyprland's main keyboard, falling
+// back to the evdev LEDs in sysfs on older compositors.
+func fetchLockKeys(hc *HyprlandClient) lockKeys {
+	if hc != nil {
+		if keyboards, err := hc.GetKeyboards(); err == nil {
+			for _, kb := range keyboards {
+				if kb.Main {
+					return lockKeys{caps: kb.CapsLock, num: kb.NumLock}
+				}
+			}
+		}
+	}
+	return lockKeys{caps: ledOn("capslock"), num: ledOn("numlock")}
+}
+
+func ledOn(name string) bool {
+	leds, _ := filepath.Glob("/sys/class/leds/*::" + name)
+	for _, led := range leds {
+		if v, err := readSysfsFloat(led, "brightness"); err == nil && v > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (k lockKeys) labels(showNum bool) []string {
+	var labels []string
+	if k.caps {
+		labels = append(labels, "CAPS")
+	}
+	if k.num && showNum {
+		labels = append(labels, "NUM")
+	}
+	return labels
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// camera and microphone kill switches. Linux has no rfkill type for
+// either, so several sources are consulted: rfkill entries some platform
+// drivers name after the device, USB video devices blocked by usbguard,
+// and the micmute LED that mirrors a hardware mic switch.
+
+type killSwitch struct {
+	kind     string // camera or mic
+	present  bool
+	disabled bool
+	source   string // rfkill, usbguard, led
+	rfkill   int
+}
+
+const usbVideoClass = "0e"
+
+func fetchKillSwitches() []killSwitch {
+	return []killSwitch{cameraSwitch(), micSwitch()}
+}
+
+func rfkillNamed(words ...string) (rfkillDevice, bool) {
+	for _, d := range listRfkill() {
+		name := strings.ToLower(d.name + " " + d.kind)
+		for _, w := range words {
+			if strings.Contains(name, w) {
+				return d, true
+			}
+		}
+	}
+	return rfkillDevice{}, false
+}
+
+func cameraSwitch() killSwitch {
+	s := killSwitch{kind: "camera"}
+	if d, ok := rfkillNamed("camera", "webcam"); ok {
+		s.present, s.disabled, s.source, s.rfkill = true, d.blocked(), "rfkill", d.index
+		return s
+	}
+
+	// usbguard knows about blocked cameras, whose interfaces disappear
+	// from sysfs once deauthorised
+	if out, err := exec.Command("usbguard", "list-devices").Output(); err == nil {
+		for line := range strings.Lines(string(out)) {
+			if !strings.Contains(line, " "+usbVideoClass+":") {
+				continue
+			}
+			s.present, s.source = true, "usbguard"
+			if strings.Contains(line, ": allow ") {
+				s.disabled = false
+				return s
+			}
+			s.disabled = true
+		}
+		return s
+	}
+
+	ifaces, _ := filepath.Glob("/sys/bus/usb/devices/*/bInterfaceClass")
+	for _, iface := range ifaces {
+		if readSysfsString(filepath.Dir(iface), "bInterfaceClass") == usbVideoClass {
+			s.present = true
+			break
+		}
+	}
+	return s
+}
+
+func micSwitch() killSwitch {
+	s := killSwitch{kind: "mic"}
+	if d, ok := rfkillNamed("mic"); ok {
+		s.present, s.disabled, s.source, s.rfkill = true, d.blocked(), "rfkill", d.index
+		return s
+	}
+	if leds, _ := filepath.Glob("/sys/class/leds/*::micmute"); len(leds) > 0 {
+		s.present, s.source = true, "led"
+		s.disabled = ledOn("micmute")
+	}
+	return s
+}
+
+// toggle flips a switch where software can: rfkill soft blocks and
+// usbguard device policy. The micmute LED only reports hardware state.
+func (s killSwitch) toggle() error {
+	switch s.source {
+	case "rfkill":
+		return setRfkill(s.rfkill, !s.disabled)
+	case "usbguard":
+		action := "block-device"
+		if s.disabled {
+			action = "allow-device"
+		}
+		return exec.Command("usbguard", action, "with-interface one-of { "+usbVideoClass+":*:* }").Run()
+	}
+	return nil
+}
+
+func (s killSwitch) toggleable() bool {
+	return s.source == "rfkill" || s.source == "usbguard"
+}
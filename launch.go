@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// runLaunch starts the bar in its own terminal window, detached from the
+// calling shell, with a class/app-id the window rules can match.
+func runLaunch(args []string) int {
+	fs := flag.NewFlagSet("launch", flag.ExitOnError)
+	terminal := fs.String("terminal", "", "terminal to use (foot, kitty, alacritty, wezterm)")
+	height := fs.Int("height", 32, "bar height in pixels")
+	apply := fs.Bool("apply", false, "apply the window rules to the running Hyprland")
+	fs.Parse(args)
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Printf("Err: cannot locate executable: %v\n", err)
+		return 1
+	}
+	argv, err := terminalCommand(*terminal, append([]string{exe}, fs.Args()...)...)
+	if err != nil {
+		fmt.Printf("Err: %v\n", err)
+		return 1
+	}
+
+	if *apply {
+		hc, err := NewHyprlandClient()
+		if err != nil {
+			fmt.Printf("Err: %v\n", err)
+			return 1
+		}
+		if err := applyHyprlandRules(hc, barWindowRules("", *height)); err != nil {
+			fmt.Printf("Err: failed to apply window rules: %v\n", err)
+			return 1
+		}
+	} else {
+		fmt.Print(hyprlandSnippet("", *height))
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		fmt.Printf("Err: failed to start %s: %v\n", argv[0], err)
+		return 1
+	}
+	cmd.Process.Release()
+	return 0
+}
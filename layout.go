@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type hitZone struct {
+	start  int
+	end    int
+	target string
+}
+
+type segments struct {
+	parts []string
+	zones []hitZone
+	width int
+}
+
+func (s *segments) add(target, rendered string) {
+	w := textWidth(rendered)
+	s.parts = append(s.parts, rendered)
+	if target != "" {
+		s.zones = append(s.zones, hitZone{start: s.width, end: s.width + w, target: target})
+	}
+	s.width += w
+}
+
+func (s *segments) pad(n int) {
+	if n <= 0 {
+		return
+	}
+	s.add("", strings.Repeat(" ", n))
+}
+
+func (s *segments) append(other segments) {
+	for _, z := range other.zones {
+		s.zones = append(s.zones, hitZone{start: z.start + s.width, end: z.end + s.width, target: z.target})
+	}
+	s.parts = append(s.parts, other.parts...)
+	s.width += other.width
+}
+
+func (s segments) render() string {
+	return s.renderAligned(lipgloss.Top)
+}
+
+func (s segments) renderAligned(align lipgloss.Position) string {
+	return lipgloss.JoinHorizontal(align, s.parts...)
+}
+
+func zoneAt(zones []hitZone, x int) string {
+	for _, z := range zones {
+		if x >= z.start && x < z.end {
+			return z.target
+		}
+	}
+	return ""
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// locale covers what the bar itself prints: day and month names, the
+// decimal and thousands separators and built-in labels. It comes from the
+// "locale" config key or LC_ALL / LC_TIME / LANG, and falls back to English.
+type locale struct {
+	lang    string
+	decimal string
+	group   string
+}
+
+type localeNames struct {
+	days, shortDays     [7]string
+	months, shortMonths [12]string
+	decimal, group      string
+	labels              map[string]string
+}
+
+var localeTable = map[string]localeNames{
+	"de": {
+		days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortDays:   [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		decimal:     ",",
+		group:       ".",
+		labels: map[string]string{
+			"disconnected":       "getrennt",
+			"muted":              "stumm",
+			"no active streams":  "keine aktiven Streams",
+			"since boot":         "seit Start",
+			"since start":        "seit Bar-Start",
+			"in":                 "in",
+			"no GPU processes":   "keine GPU-Prozesse",
+			"no sessions":        "keine Sitzungen",
+			"nothing played yet": "noch nichts gespielt",
+			"no history yet":     "noch kein Verlauf",
+			"no notifications":   "keine Benachrichtigungen",
+			"no firewall":        "keine Firewall",
+			"none":               "keine",
+		},
+	},
+	"fr": {
+		days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortDays:   [7]string{"dim", "lun", "mar", "mer", "jeu", "ven", "sam"},
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: [12]string{"janv", "févr", "mars", "avr", "mai", "juin", "juil", "août", "sept", "oct", "nov", "déc"},
+		decimal:     ",",
+		group:       "\u202f",
+		labels: map[string]string{
+			"disconnected":       "déconnecté",
+			"muted":              "muet",
+			"no active streams":  "aucun flux actif",
+			"since boot":         "depuis le boot",
+			"since start":        "depuis le lancement",
+			"in":                 "dans",
+			"no GPU processes":   "aucun processus GPU",
+			"no sessions":        "aucune session",
+			"nothing played yet": "rien écouté pour l'instant",
+			"no history yet":     "pas encore d'historique",
+			"no notifications":   "aucune notification",
+			"no firewall":        "pas de pare-feu",
+			"none":               "aucun",
+		},
+	},
+	"es": {
+		days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+		decimal:     ",",
+		group:       ".",
+		labels: map[string]string{
+			"disconnected":       "desconectado",
+			"muted":              "silencio",
+			"no active streams":  "sin flujos activos",
+			"since boot":         "desde el arranque",
+			"since start":        "desde el inicio",
+			"in":                 "en",
+			"no GPU processes":   "sin procesos de GPU",
+			"no sessions":        "sin sesiones",
+			"nothing played yet": "nada reproducido todavía",
+			"no history yet":     "aún no hay historial",
+			"no notifications":   "sin notificaciones",
+			"no firewall":        "sin cortafuegos",
+			"none":               "ninguno",
+		},
+	},
+	"it": {
+		days:        [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		shortDays:   [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		months:      [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		shortMonths: [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		decimal:     ",",
+		group:       ".",
+		labels: map[string]string{
+			"disconnected":       "disconnesso",
+			"muted":              "muto",
+			"no active streams":  "nessun flusso attivo",
+			"since boot":         "dall'avvio",
+			"since start":        "dall'apertura",
+			"in":                 "tra",
+			"no GPU processes":   "nessun processo GPU",
+			"no sessions":        "nessuna sessione",
+			"nothing played yet": "ancora niente riprodotto",
+			"no history yet":     "ancora nessuna cronologia",
+			"no notifications":   "nessuna notifica",
+			"no firewall":        "nessun firewall",
+			"none":               "nessuno",
+		},
+	},
+	"nl": {
+		days:        [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		shortDays:   [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		months:      [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		shortMonths: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		decimal:     ",",
+		group:       ".",
+		labels: map[string]string{
+			"disconnected":       "verbroken",
+			"muted":              "gedempt",
+			"no active streams":  "geen actieve streams",
+			"since boot":         "sinds opstarten",
+			"since start":        "sinds bar-start",
+			"in":                 "over",
+			"no GPU processes":   "geen GPU-processen",
+			"no sessions":        "geen sessies",
+			"nothing played yet": "nog niets afgespeeld",
+			"no history yet":     "nog geen geschiedenis",
+			"no notifications":   "geen meldingen",
+			"no firewall":        "geen firewall",
+			"none":               "geen",
+		},
+	},
+}
+
+// newLocale resolves the locale; without grouping large numbers are
+// printed as plain digits.
+func newLocale(override string, grouping bool) locale {
+	name := override
+	for _, env := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if name != "" {
+			break
+		}
+		name = os.Getenv(env)
+	}
+
+	// de_DE.UTF-8, de-DE, de
+	lang := strings.ToLower(name)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	l := locale{lang: "en", decimal: ".", group: ","}
+	if names, ok := localeTable[lang]; ok {
+		l = locale{lang: lang, decimal: names.decimal, group: names.group}
+	}
+	if !grouping {
+		l.group = ""
+	}
+	return l
+}
+
+// formatTime is time.Format with day and month names translated.
+func (l locale) formatTime(t time.Time, layout string) string {
+	names, ok := localeTable[l.lang]
+	if !ok {
+		return t.Format(layout)
+	}
+
+	var b strings.Builder
+	for layout != "" {
+		next, token, name := len(layout), "", ""
+		for _, candidate := range []struct{ token, name string }{
+			{"Monday", names.days[t.Weekday()]},
+			{"Mon", names.shortDays[t.Weekday()]},
+			{"January", names.months[t.Month()-1]},
+			{"Jan", names.shortMonths[t.Month()-1]},
+		} {
+			if i := strings.Index(layout, candidate.token); i >= 0 && i < next {
+				next, token, name = i, candidate.token, candidate.name
+			}
+		}
+		b.WriteString(t.Format(layout[:next]))
+		b.WriteString(name)
+		layout = layout[next+len(token):]
+	}
+	return b.String()
+}
+
+func (l locale) float(v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	whole, frac, ok := strings.Cut(s, ".")
+	if !ok {
+		return l.grouped(whole)
+	}
+	return l.grouped(whole) + l.decimal + frac
+}
+
+func (l locale) int(n int64) string {
+	return l.grouped(strconv.FormatInt(n, 10))
+}
+
+// grouped puts the thousands separator into a run of digits: 1234567
+// becomes 1,234,567 in English and 1.234.567 in German.
+func (l locale) grouped(digits string) string {
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if l.group == "" || len(digits) <= 3 {
+		return sign + digits
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(l.group)
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+func (l locale) text(label string) string {
+	if translated, ok := localeTable[l.lang].labels[label]; ok {
+		return translated
+	}
+	return label
+}
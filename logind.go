@@ -0,0 +1,101 @@
+package main
+
+import (
+	"cmp"
+	"os"
+	"slices"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// sessions and seats from systemd-logind
+
+const (
+	logindService = "org.freedesktop.login1"
+	logindPath    = "/org/freedesktop/login1"
+	logindSession = logindService + ".Session"
+)
+
+type loginSession struct {
+	id      string
+	user    string
+	seat    string
+	kind    string // wayland, x11, tty, ...
+	class   string // user, greeter, lock-screen, ...
+	vt      uint32
+	active  bool
+	remote  bool
+	current bool // the session the bar runs in
+}
+
+// graphical is true for the sessions a person sits in front of, leaving
+// out text consoles, ssh logins and the greeter.
+func (s loginSession) graphical() bool {
+	return (s.kind == "wayland" || s.kind == "x11") && s.class == "user" && !s.remote
+}
+
+// fetchSessions lists logind's sessions, sorted by seat and then id.
+func fetchSessions() ([]loginSession, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	var listed []struct {
+		ID   string
+		UID  uint32
+		User string
+		Seat string
+		Path dbus.ObjectPath
+	}
+	if err := conn.Object(logindService, logindPath).Call(logindService+".Manager.ListSessions", 0).Store(&listed); err != nil {
+		return nil, err
+	}
+
+	current := currentSessionID(conn)
+	sessions := make([]loginSession, 0, len(listed))
+	for _, l := range listed {
+		s := loginSession{id: l.ID, user: l.User, seat: l.Seat, current: l.ID == current}
+		var props map[string]dbus.Variant
+		if err := conn.Object(logindService, l.Path).Call("org.freedesktop.DBus.Properties.GetAll", 0, logindSession).Store(&props); err == nil {
+			s.kind, _ = props["Type"].Value().(string)
+			s.class, _ = props["Class"].Value().(string)
+			s.vt, _ = props["VTNr"].Value().(uint32)
+			s.active = variantBool(props["Active"])
+			s.remote = variantBool(props["Remote"])
+		}
+		sessions = append(sessions, s)
+	}
+	slices.SortFunc(sessions, func(a, b loginSession) int {
+		// seatless sessions (ssh, services) go last
+		if (a.seat == "") != (b.seat == "") {
+			if a.seat == "" {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(cmp.Compare(a.seat, b.seat), cmp.Compare(a.id, b.id))
+	})
+	return sessions, nil
+}
+
+// currentSessionID is $XDG_SESSION_ID, or the session logind puts this
+// process in when that isn't set. Under a user service there is none.
+func currentSessionID(conn *dbus.Conn) string {
+	if id := os.Getenv("XDG_SESSION_ID"); id != "" {
+		return id
+	}
+	return sessionOfPID(conn, os.Getpid())
+}
+
+func sessionOfPID(conn *dbus.Conn, pid int) string {
+	var path dbus.ObjectPath
+	if err := conn.Object(logindService, logindPath).Call(logindService+".Manager.GetSessionByPID", 0, uint32(pid)).Store(&path); err != nil {
+		return ""
+	}
+	id, err := conn.Object(logindService, path).GetProperty(logindSession + ".Id")
+	if err != nil {
+		return ""
+	}
+	s, _ := id.Value().(string)
+	return s
+}
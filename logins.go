@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// failedLogins are authentication failures seen in the journal, by the
+// service that logged them.
+type failedLogins struct {
+	ssh  int
+	sudo int
+}
+
+func (f failedLogins) total() int {
+	return f.ssh + f.sudo
+}
+
+// fetchFailedLogins counts failed SSH and sudo attempts logged within
+// window. Reading other users' journal entries takes the systemd-journal
+// or adm group.
+func fetchFailedLogins(window time.Duration) (failedLogins, error) {
+	since := fmt.Sprintf("-%ds", int(window.Seconds()))
+	out, err := exec.Command("journalctl", "--quiet", "--no-pager", "--output=cat", "--since="+since,
+		"SYSLOG_IDENTIFIER=sshd", "SYSLOG_IDENTIFIER=sshd-session", "SYSLOG_IDENTIFIER=sudo").Output()
+	if err != nil {
+		return failedLogins{}, err
+	}
+
+	var f failedLogins
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		message := scanner.Text()
+		switch {
+		case strings.HasPrefix(message, "Failed "), strings.HasPrefix(message, "Invalid user "):
+			f.ssh++
+		case strings.HasPrefix(message, "pam_unix(sudo:auth): authentication failure"):
+			f.sudo++
+		}
+	}
+	return f, nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -8,14 +9,62 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "msg":
+			os.Exit(runMsgCommand(os.Args[2:]))
+		case "install-service":
+			os.Exit(runInstallService(os.Args[2:]))
+		case "theme":
+			os.Exit(runThemeCLI(os.Args[2:]))
+		case "launch":
+			os.Exit(runLaunch(os.Args[2:]))
+		case "setup-hyprland":
+			os.Exit(runSetupHyprland(os.Args[2:]))
+		}
+	}
+
+	supervise := flag.Bool("supervise", false, "restart the bar with backoff if it crashes")
+	pprofAddr := flag.String("pprof", "", "serve net/http/pprof on this address, e.g. :6060")
+	inline := flag.Bool("inline", false, "render in the current buffer instead of the alternate screen")
+	flag.Parse()
+	if *supervise {
+		os.Exit(runSupervisor(withoutFlag(os.Args[1:], "supervise")))
+	}
+	if f, err := logToFile(); err == nil {
+		defer f.Close()
+	}
+	startPprof(*pprofAddr)
+
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Printf("Err: failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *inline {
+		config.Inline = true
+	}
+	applyAccessibility(config.Accessibility)
 
-	p := tea.NewProgram(
-		initModel(),
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
+	options := []tea.ProgramOption{tea.WithMouseCellMotion()}
+	if !config.Inline {
+		options = append(options, tea.WithAltScreen())
+	}
+	if config.MaxFPS > 0 {
+		options = append(options, tea.WithFPS(config.MaxFPS))
+	}
+	p := tea.NewProgram(initModel(config), options...)
 
-	if _, err := p.Run(); err != nil {
+	finalModel, err := p.Run()
+	sdNotify("STOPPING=1")
+	if m, ok := finalModel.(model); ok {
+		m.shutdown()
+		if err := saveState(m.snapshotState()); err != nil {
+			fmt.Printf("Err: failed to save state: %v\n", err)
+		}
+	}
+	if err != nil {
 		fmt.Printf("Err: program failed to run: %v\n", err)
 		os.Exit(1)
 	}
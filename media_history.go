@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// the media module's recent tracks, kept in the runtime state so they
+// survive restarts and players closing, and shown in its popup and by
+// `tui-bar msg stats media`
+
+const trackHistorySize = 50
+
+// a track has to play this long before it counts, so skipping through a
+// playlist doesn't flood the history
+const trackMinPlayed = 30 * time.Second
+
+type playedTrack struct {
+	Artist string    `json:"artist,omitempty"`
+	Title  string    `json:"title"`
+	Album  string    `json:"album,omitempty"`
+	Player string    `json:"player,omitempty"`
+	Played time.Time `json:"played"`
+}
+
+func (t playedTrack) same(o playedTrack) bool {
+	return t.Artist == o.Artist && t.Title == o.Title && t.Album == o.Album
+}
+
+func (t playedTrack) label() string {
+	if t.Artist == "" {
+		return t.Title
+	}
+	return t.Artist + " – " + t.Title
+}
+
+// trackHistory holds played tracks, most recent first, plus the one that
+// is playing now, which only goes in once it has played long enough.
+type trackHistory struct {
+	tracks  []playedTrack
+	current playedTrack
+	since   time.Time
+}
+
+// play notes the track now playing; an empty title means playback stopped.
+func (h *trackHistory) play(track playedTrack, now time.Time) {
+	if h.current.Title != "" && track.same(h.current) {
+		return
+	}
+	h.commit(now)
+	h.current = track
+	h.since = now
+}
+
+// commit moves the current track into the history if it played for long
+// enough and isn't already at the top.
+func (h *trackHistory) commit(now time.Time) {
+	if h.current.Title == "" || now.Sub(h.since) < trackMinPlayed {
+		return
+	}
+	if len(h.tracks) > 0 && h.tracks[0].same(h.current) {
+		return
+	}
+	track := h.current
+	track.Played = h.since
+	h.tracks = append([]playedTrack{track}, h.tracks...)
+	if len(h.tracks) > trackHistorySize {
+		h.tracks = h.tracks[:trackHistorySize]
+	}
+}
+
+// recent returns up to n tracks, most recent first.
+func (h *trackHistory) recent(n int) []playedTrack {
+	return h.tracks[:min(n, len(h.tracks))]
+}
+
+// save includes the track that's playing, so quitting mid-song keeps it.
+func (h *trackHistory) save() any {
+	saved := *h
+	saved.commit(time.Now())
+	return saved.tracks
+}
+
+func (h *trackHistory) restore(data json.RawMessage) {
+	json.Unmarshal(data, &h.tracks)
+}
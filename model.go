@@ -1,53 +1,179 @@
 package main
 
 import (
-	tea "github.com/charmbracelet/bubbletea"
+	"context"
+	"log"
+	"os"
+	"sync"
 	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 type model struct {
-	currTime  time.Time
-	cpuUsage  float64
-	memUsage  float64
-	diskUsage float64
+	modules    []Module
+	moduleByID map[string]Module
+	sections   [][]string
 
-	netName  string
-	netState string
+	width  int
+	height int
 
-	batLevel int
-	batState string
+	hidden        bool
+	focus         bool
+	present       bool
+	screencast    bool
+	recording     bool
+	workspace     workspaceFocusMsg
+	theme         string
+	themeOverride string
+	preview       *themePreview
+	popup         string
+	hover         string
+	lastActivity  time.Time
+	savedState    string
+	lastWatchdog  time.Time
+	lastSpoken    time.Time
+	spoken        string
 
-	activeWorkspace int
-	windowTitle     string
+	hypr       *HyprlandClient
+	hyprEvents <-chan HyprlandEvent
+	lastEvents time.Time
+	ipc        *IPCServer
+	faults     *moduleFaults
+	frames     *frameStats
+	locale     locale
+	metrics    *MetricsCollector
+	network    *NetworkWatcher
+	appearance *AppearanceWatcher
+	config     *Config
 
-	width  int
-	height int
+	cancel    context.CancelFunc
+	closeOnce *sync.Once
+}
+
+func initModel(config *Config) model {
+	hypr, _ := NewHyprlandClient()
+	if hypr != nil {
+		hypr.AllowRaw(config.Hyprctl.Allow)
+	}
+
+	metrics := NewMetricsCollector(config.Metrics, config.RefreshInterval)
+	metrics.Start()
+
+	var events <-chan HyprlandEvent
+	if hypr != nil {
+		handler := NewHyprlandEventHandler(hypr)
+		if err := handler.Start(); err == nil {
+			events = handler.Events()
+		}
+	}
 
-	hypr *HyprlandClient
+	m := newModel(config, hypr, metrics)
+	m.hyprEvents = events
+	m.ipc = startIPCServer()
+	m.network.Start()
+	if config.Theme.Mode == "system" {
+		m.appearance = newAppearanceWatcher()
+		if err := m.appearance.Start(); err != nil {
+			log.Printf("appearance portal: %v", err)
+		}
+	}
+	return m.restoreState(loadState()).updateTheme(time.Now())
 }
 
-func initModel() model {
+func newModel(config *Config, hypr *HyprlandClient, metrics *MetricsCollector) model {
+	lifetime, cancel := context.WithCancel(context.Background())
+	faults := newModuleFaults()
+	frames := &frameStats{}
+	client := newHTTPClient(config.HTTP)
+	network := newNetworkWatcher(config.Network, client)
+	locale := newLocale(config.Locale, config.Units.Grouping)
+	modules := newModules(&moduleContext{
+		lifetime: lifetime,
+		hypr:     hypr,
+		metrics:  metrics,
+		network:  network,
+		http:     client,
+		locale:   locale,
+		units:    newUnits(config.Units, locale),
+		config:   config,
+		faults:   faults,
+		frames:   frames,
+	})
+	sections := layoutSections(config)
+	setModuleBorders(config.Borders, sections)
+	setDensity(config.Bar.Density)
+	terminalBidi = config.Bar.TerminalBidi
+
+	moduleByID := make(map[string]Module, len(modules))
+	for _, mod := range modules {
+		moduleByID[mod.Name()] = mod
+	}
+
 	return model{
-		currTime:        time.Now(),
-		cpuUsage:        0,
-		memUsage:        0,
-		diskUsage:       0,
-		netName:         "wlan0",
-		netState:        "disconnected",
-		batLevel:        0,
-		batState:        "unknown",
-		activeWorkspace: 1,
-		windowTitle:     "",
-		width:           0,
-		height:          0,
+		modules:      modules,
+		moduleByID:   moduleByID,
+		sections:     sections,
+		width:        0,
+		height:       0,
+		lastActivity: time.Now(),
+		hypr:         hypr,
+		faults:       faults,
+		frames:       frames,
+		locale:       locale,
+		metrics:      metrics,
+		network:      network,
+		config:       config,
+		cancel:       cancel,
+		closeOnce:    &sync.Once{},
 	}
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(
+	var mouseCmd tea.Cmd
+	if m.wantsAllMotion() {
+		mouseCmd = tea.EnableMouseAllMotion
+	}
+
+	cmds := []tea.Cmd{
+		mouseCmd,
+		spinnerCmd(),
 		tickCmd(),
-		getSystemInfo(),
-		getBatteryInfo(),
-		getNetworkInfo(),
-	)
+		m.waitForHyprlandEvents(),
+		fetchWorkspaceFocus(m.hypr),
+		waitForIPC(m.ipc),
+	}
+	for _, mod := range m.modules {
+		cmds = append(cmds, m.faults.init(mod))
+	}
+	if m.focus {
+		cmds = append(cmds, m.broadcast(focusMsg(true)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// shutdown cancels the modules' lifetime and releases everything the bar
+// started: the Hyprland event reader and its subscribers, the metrics
+// and network pollers, the appearance watcher and the IPC socket. Safe
+// to call more than once.
+func (m model) shutdown() {
+	m.closeOnce.Do(func() {
+		m.cancel()
+		if m.hypr != nil {
+			m.hypr.Close()
+		}
+		if m.metrics != nil {
+			m.metrics.Stop()
+		}
+		m.network.Stop()
+		if m.appearance != nil {
+			m.appearance.Stop()
+		}
+		if m.ipc != nil {
+			m.ipc.Close()
+		}
+		if f, ok := log.Writer().(*os.File); ok {
+			f.Sync()
+		}
+	})
 }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type rfkillMsg []rfkillDevice
+
+// AirplaneModule reflects the wifi and bluetooth radio state and toggles
+// both at once on click.
+type AirplaneModule struct {
+	ctx     *moduleContext
+	devices []rfkillDevice
+}
+
+func init() {
+	RegisterModule("airplane", func(ctx *moduleContext) Module {
+		return newAirplaneModule(ctx)
+	})
+}
+
+func newAirplaneModule(ctx *moduleContext) *AirplaneModule {
+	return &AirplaneModule{ctx: ctx}
+}
+
+func (m *AirplaneModule) Name() string {
+	return "airplane"
+}
+
+func (m *AirplaneModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *AirplaneModule) fetch() tea.Cmd {
+	return moduleCmd(m.Name(), func() tea.Msg {
+		return rfkillMsg(listRfkill())
+	})
+}
+
+func (m *AirplaneModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		return m.fetch()
+	case rfkillMsg:
+		m.devices = msg
+	}
+	return nil
+}
+
+// radios reports whether any device of kind exists and whether any is on.
+func (m *AirplaneModule) radios(kind string) (present, on bool) {
+	for _, d := range m.devices {
+		if d.kind == kind {
+			present = true
+			on = on || !d.blocked()
+		}
+	}
+	return present, on
+}
+
+func (m *AirplaneModule) airplane() bool {
+	_, wifi := m.radios("wlan")
+	_, bt := m.radios("bluetooth")
+	return len(m.devices) > 0 && !wifi && !bt
+}
+
+func (m *AirplaneModule) Vars(vars exprVars) {
+	_, wifi := m.radios("wlan")
+	_, bt := m.radios("bluetooth")
+	vars["airplane.on"] = m.airplane()
+	vars["airplane.wifi"] = wifi
+	vars["airplane.bluetooth"] = bt
+}
+
+func (m *AirplaneModule) Render() string {
+	if len(m.devices) == 0 {
+		return ""
+	}
+	if m.airplane() {
+		return icon("󰀝")
+	}
+	out := icon("󰀞")
+	if present, on := m.radios("wlan"); present {
+		out += " " + radioIcon(on, "󰖩", "󰖪")
+	}
+	if present, on := m.radios("bluetooth"); present {
+		out += " " + radioIcon(on, "󰂯", "󰂲")
+	}
+	return out
+}
+
+func radioIcon(on bool, onIcon, offIcon string) string {
+	if on {
+		return icon(onIcon)
+	}
+	return icon(offIcon)
+}
+
+func (m *AirplaneModule) Style() lipgloss.Style {
+	if m.airplane() {
+		return warningStyle
+	}
+	return boxStyle
+}
+
+func (m *AirplaneModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type != tea.MouseLeft {
+		return nil
+	}
+	block := !m.airplane()
+	return moduleCmd(m.Name(), func() tea.Msg {
+		for _, kind := range []string{"wlan", "bluetooth"} {
+			if err := setRfkillType(kind, block); err != nil {
+				log.Printf("airplane: %s: %v", kind, err)
+			}
+		}
+		return rfkillMsg(listRfkill())
+	})
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BackupConfig lists the backups to watch; the bar shows the oldest and
+// turns critical once any is older than StaleHours or can't be read.
+type BackupConfig struct {
+	Sources    []BackupSource `json:"sources"`
+	StaleHours int            `json:"stale_hours"`
+}
+
+type backupStatus struct {
+	name string
+	at   time.Time
+	err  error
+}
+
+type backupMsg []backupStatus
+
+// backupInterval is how many ticks pass between checks; restic and borg
+// open the repository each time.
+const backupInterval = 600
+
+// BackupModule shows the time since the last successful backup. Left
+// click opens a popup with every source.
+type BackupModule struct {
+	ctx      *moduleContext
+	statuses []backupStatus
+	now      time.Time
+	ticks    int
+	ready    bool
+}
+
+func init() {
+	RegisterModule("backup", func(ctx *moduleContext) Module {
+		return &BackupModule{ctx: ctx, now: time.Now()}
+	})
+}
+
+func (m *BackupModule) Name() string {
+	return "backup"
+}
+
+func (m *BackupModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *BackupModule) fetch() tea.Cmd {
+	sources := m.ctx.config.Backup.Sources
+	if len(sources) == 0 {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		statuses := make([]backupStatus, len(sources))
+		for i, source := range sources {
+			at, err := source.lastBackup()
+			if err != nil {
+				log.Printf("backup: %s: %v", source.label(), err)
+			}
+			statuses[i] = backupStatus{name: source.label(), at: at, err: err}
+		}
+		return backupMsg(statuses)
+	})
+}
+
+func (m *BackupModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.now = time.Time(msg)
+		m.ticks++
+		if m.ticks%backupInterval == 0 {
+			return m.fetch()
+		}
+	case backupMsg:
+		m.statuses = msg
+		m.ready = true
+	}
+	return nil
+}
+
+func (m *BackupModule) Ready() bool {
+	return m.ready
+}
+
+// oldest is the stalest source, failures first.
+func (m *BackupModule) oldest() (backupStatus, bool) {
+	if len(m.statuses) == 0 {
+		return backupStatus{}, false
+	}
+	oldest := m.statuses[0]
+	for _, s := range m.statuses[1:] {
+		if s.err != nil && oldest.err == nil || s.err == nil && oldest.err == nil && s.at.Before(oldest.at) {
+			oldest = s
+		}
+	}
+	return oldest, true
+}
+
+func (m *BackupModule) stale(s backupStatus) bool {
+	return s.err != nil || m.now.Sub(s.at) > time.Duration(m.ctx.config.Backup.StaleHours)*time.Hour
+}
+
+func (m *BackupModule) Vars(vars exprVars) {
+	if s, ok := m.oldest(); ok {
+		vars["backup.stale"] = m.stale(s)
+		if s.err == nil {
+			vars["backup.age_hours"] = m.now.Sub(s.at).Hours()
+		}
+	}
+}
+
+// backupAge is whole days, hours or minutes.
+func backupAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dm", max(int(d.Minutes()), 0))
+}
+
+func (m *BackupModule) Describe() string {
+	s, ok := m.oldest()
+	switch {
+	case !ok:
+		return ""
+	case s.err != nil:
+		return "backup " + s.name + " failed"
+	}
+	return "last backup " + backupAge(m.now.Sub(s.at)) + " ago"
+}
+
+func (m *BackupModule) Render() string {
+	s, ok := m.oldest()
+	switch {
+	case !ok:
+		return ""
+	case s.err != nil:
+		return icon("󰁯") + " " + truncate(s.name, 16) + " ?"
+	}
+	return icon("󰁯") + " " + backupAge(m.now.Sub(s.at))
+}
+
+func (m *BackupModule) Style() lipgloss.Style {
+	if s, ok := m.oldest(); ok && m.stale(s) {
+		return criticalStyle
+	}
+	return boxStyle
+}
+
+func (m *BackupModule) Popup() []segments {
+	rows := make([]segments, 0, len(m.statuses))
+	width := 0
+	for _, s := range m.statuses {
+		width = max(width, textWidth(s.name))
+	}
+	for _, s := range m.statuses {
+		age := "failed"
+		if s.err == nil {
+			age = backupAge(m.now.Sub(s.at))
+		}
+		mark := " "
+		if m.stale(s) {
+			mark = "!"
+		}
+		var row segments
+		row.add("", fmt.Sprintf(" %s %6s %s", padRight(s.name, width), age, mark))
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (m *BackupModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		return togglePopup(m.Name())
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type batteryMsg batteryStats
+type peripheralsMsg []peripheralBattery
+
+type BatteryModule struct {
+	ctx         *moduleContext
+	stats       batteryStats
+	peripherals []peripheralBattery
+	ticks       int
+	ready       bool
+}
+
+func init() {
+	RegisterModule("battery", func(ctx *moduleContext) Module {
+		return newBatteryModule(ctx)
+	})
+}
+
+func newBatteryModule(ctx *moduleContext) *BatteryModule {
+	return &BatteryModule{
+		ctx:   ctx,
+		stats: batteryStats{state: "unknown"},
+	}
+}
+
+func (m *BatteryModule) Name() string {
+	return "battery"
+}
+
+func (m *BatteryModule) Init() tea.Cmd {
+	return tea.Batch(m.fetch(), m.fetchPeripherals())
+}
+
+func (m *BatteryModule) fetch() tea.Cmd {
+	return moduleCmd(m.Name(), func() tea.Msg {
+		return batteryMsg(fetchBatteryStats())
+	})
+}
+
+func (m *BatteryModule) fetchPeripherals() tea.Cmd {
+	if m.ctx.config.Battery.PeripheralLow <= 0 {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		batteries, err := fetchPeripheralBatteries()
+		if err != nil {
+			return nil
+		}
+		return peripheralsMsg(batteries)
+	})
+}
+
+func (m *BatteryModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if m.ticks%30 == 0 {
+			return tea.Batch(m.fetch(), m.fetchPeripherals())
+		}
+		return m.fetch()
+	case batteryMsg:
+		m.stats = batteryStats(msg)
+		m.ready = true
+	case peripheralsMsg:
+		m.peripherals = msg
+	}
+	return nil
+}
+
+// lowPeripherals are the controllers and styluses worth a warning.
+func (m *BatteryModule) lowPeripherals() []peripheralBattery {
+	var low []peripheralBattery
+	for _, p := range m.peripherals {
+		if !p.charging && p.level <= m.ctx.config.Battery.PeripheralLow {
+			low = append(low, p)
+		}
+	}
+	return low
+}
+
+// capped reports a firmware charge limit the battery is sitting behind,
+// so that stopping at 60% reads as intended rather than as a fault.
+func (m *BatteryModule) capped() bool {
+	return m.stats.limit > 0 && m.stats.state != "discharging"
+}
+
+func (m *BatteryModule) Ready() bool {
+	return m.ready
+}
+
+func (m *BatteryModule) Vars(vars exprVars) {
+	vars["battery.present"] = m.stats.present
+	vars["battery.health"] = m.stats.health
+	vars["battery.level"] = m.stats.level
+	vars["battery.state"] = m.stats.state
+	vars["battery.limit"] = m.stats.limit
+	vars["battery.peripherals_low"] = len(m.lowPeripherals())
+}
+
+func (m *BatteryModule) Describe() string {
+	if !m.stats.present {
+		return ""
+	}
+	text := fmt.Sprintf("Battery %d percent", m.stats.level)
+	if m.stats.state == "charging" || m.stats.state == "full" {
+		text += " " + m.stats.state
+	}
+	if m.capped() {
+		text += fmt.Sprintf(", charge limited to %d percent", m.stats.limit)
+	}
+	return text
+}
+
+func (m *BatteryModule) Render() string {
+	batIcon := getBatteryIcon(m.stats.level, m.stats.state)
+	text := fmt.Sprintf("%s %d%%", batIcon, m.stats.level)
+	if m.capped() {
+		text += " " + icon("󰕥")
+	}
+	return text
+}
+
+func (m *BatteryModule) Segments() segments {
+	var s segments
+	if m.stats.present {
+		s.add("", m.Style().Render(m.Render()))
+	}
+	for _, p := range m.lowPeripherals() {
+		s.add("peripheral", criticalStyle.Render(fmt.Sprintf("%s %d%%", peripheralIcon(p.kind), p.level)))
+	}
+	return s
+}
+
+func (m *BatteryModule) Style() lipgloss.Style {
+	if m.stats.state == "charging" {
+		return batteryChargingStyle
+	} else if m.stats.level < 20 {
+		return batteryLowStyle
+	}
+	return batteryStyle
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BreakConfig drives 20-20-20 style reminders: after IntervalMinutes of
+// active time the module flashes and runs Command, and looking away for
+// BreakSeconds counts as the break. Being idle for IdleSeconds pauses
+// the count and, being a break in itself, resets it.
+type BreakConfig struct {
+	IntervalMinutes int      `json:"interval_minutes"`
+	BreakSeconds    int      `json:"break_seconds"`
+	IdleSeconds     int      `json:"idle_seconds"`
+	Command         []string `json:"command"`
+}
+
+type BreakModule struct {
+	ctx      *moduleContext
+	activity activity
+	active   time.Duration
+	lastTick time.Time
+	due      bool
+	ticks    int
+}
+
+func init() {
+	RegisterModule("break", func(ctx *moduleContext) Module {
+		return newBreakModule(ctx)
+	})
+}
+
+func newBreakModule(ctx *moduleContext) *BreakModule {
+	return &BreakModule{ctx: ctx, activity: newActivity()}
+}
+
+func (m *BreakModule) Name() string {
+	return "break"
+}
+
+func (m *BreakModule) Init() tea.Cmd {
+	return nil
+}
+
+func (m *BreakModule) interval() time.Duration {
+	return time.Duration(max(m.ctx.config.Break.IntervalMinutes, 1)) * time.Minute
+}
+
+func (m *BreakModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		now := time.Time(msg)
+		m.count(now)
+		return fetchCursor(m.Name(), m.ctx.hypr)
+	default:
+		m.activity.observe(msg)
+	}
+	return nil
+}
+
+func (m *BreakModule) count(now time.Time) {
+	cfg := m.ctx.config.Break
+	elapsed, ok := tickElapsed(&m.lastTick, now)
+	if !ok {
+		return
+	}
+
+	idle := m.activity.idle()
+	if (cfg.IdleSeconds > 0 && idle >= time.Duration(cfg.IdleSeconds)*time.Second) ||
+		(m.due && idle >= time.Duration(cfg.BreakSeconds)*time.Second) {
+		m.reset()
+		return
+	}
+
+	m.active += elapsed
+	if !m.due && m.active >= m.interval() {
+		m.due = true
+		m.remind()
+	}
+}
+
+func (m *BreakModule) reset() {
+	m.active, m.due = 0, false
+}
+
+func (m *BreakModule) remind() {
+	command := m.ctx.config.Break.Command
+	if len(command) == 0 {
+		return
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		log.Printf("break: %v", err)
+		return
+	}
+	go cmd.Wait()
+}
+
+func (m *BreakModule) remaining() time.Duration {
+	return max(m.interval()-m.active, 0)
+}
+
+func (m *BreakModule) Vars(vars exprVars) {
+	vars["break.due"] = m.due
+	vars["break.remaining"] = int(m.remaining().Seconds())
+}
+
+func (m *BreakModule) Describe() string {
+	if m.due {
+		return "time for a break"
+	}
+	return ""
+}
+
+func (m *BreakModule) Render() string {
+	if m.due {
+		return icon("󰈈") + " break"
+	}
+	return fmt.Sprintf("%s %dm", icon("󰈈"), int(m.remaining().Minutes())+1)
+}
+
+func (m *BreakModule) Style() lipgloss.Style {
+	if m.due {
+		// flash
+		if m.ticks%2 == 0 {
+			return criticalStyle
+		}
+		return warningStyle
+	}
+	return boxStyle
+}
+
+// HandleMouse: left click acknowledges the reminder (or restarts the
+// count), as if the break had been taken.
+func (m *BreakModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		m.reset()
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// caffeineSteps is the click cycle; a negative duration holds the
+// inhibitor until switched off.
+var caffeineSteps = []time.Duration{0, 30 * time.Minute, time.Hour, -1}
+
+type caffeineMsg struct {
+	lock *os.File
+	step int
+}
+
+// CaffeineModule keeps the session awake with a logind idle/sleep
+// inhibitor. Left click cycles off → 30m → 1h → ∞, right click turns it
+// off, and a timed inhibitor is released once it runs out.
+type CaffeineModule struct {
+	ctx   *moduleContext
+	lock  *os.File
+	step  int
+	until time.Time
+}
+
+func init() {
+	RegisterModule("caffeine", func(ctx *moduleContext) Module {
+		return newCaffeineModule(ctx)
+	})
+}
+
+func newCaffeineModule(ctx *moduleContext) *CaffeineModule {
+	return &CaffeineModule{ctx: ctx}
+}
+
+func (m *CaffeineModule) Name() string {
+	return "caffeine"
+}
+
+func (m *CaffeineModule) Init() tea.Cmd {
+	return nil
+}
+
+func (m *CaffeineModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		if m.lock != nil && !m.until.IsZero() && time.Time(msg).After(m.until) {
+			m.release()
+		}
+	case caffeineMsg:
+		m.release()
+		m.lock, m.step = msg.lock, msg.step
+		if d := caffeineSteps[m.step]; d > 0 {
+			m.until = time.Now().Add(d)
+		}
+	}
+	return nil
+}
+
+func (m *CaffeineModule) release() {
+	if m.lock != nil {
+		m.lock.Close()
+	}
+	m.lock, m.step, m.until = nil, 0, time.Time{}
+}
+
+func (m *CaffeineModule) remaining() time.Duration {
+	if m.until.IsZero() {
+		return 0
+	}
+	return max(time.Until(m.until), 0)
+}
+
+func (m *CaffeineModule) Vars(vars exprVars) {
+	vars["caffeine.active"] = m.lock != nil
+	vars["caffeine.remaining"] = int(m.remaining().Seconds())
+}
+
+func (m *CaffeineModule) Describe() string {
+	if m.lock == nil {
+		return ""
+	}
+	if m.until.IsZero() {
+		return "staying awake"
+	}
+	return fmt.Sprintf("staying awake for %d minutes", int(m.remaining().Minutes())+1)
+}
+
+func (m *CaffeineModule) Render() string {
+	if m.lock == nil {
+		return icon("󰾪")
+	}
+	if m.until.IsZero() {
+		return icon("󰅶") + " ∞"
+	}
+	return icon("󰅶") + " " + shortDuration(max(m.remaining(), time.Minute))
+}
+
+func (m *CaffeineModule) Style() lipgloss.Style {
+	if m.lock != nil {
+		return activeBoxStyle
+	}
+	return boxStyle
+}
+
+func (m *CaffeineModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	step := 0
+	switch msg.Type {
+	case tea.MouseLeft:
+		step = (m.step + 1) % len(caffeineSteps)
+	case tea.MouseRight:
+	default:
+		return nil
+	}
+	if step == 0 {
+		m.release()
+		return nil
+	}
+	// a fresh lock is taken before the old one is dropped
+	return moduleCmd(m.Name(), func() tea.Msg {
+		lock, err := inhibitIdle("caffeine")
+		if err != nil {
+			log.Printf("caffeine: %v", err)
+			return nil
+		}
+		return caffeineMsg{lock: lock, step: step}
+	})
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CgroupConfig names the cgroup to watch, either a path under
+// /sys/fs/cgroup or a systemd unit such as "build.slice" or "make.scope".
+// CPU is a share of the whole machine; the module turns warning above
+// WarnPercent of the cgroup's memory limit.
+type CgroupConfig struct {
+	Path        string `json:"path"`
+	Label       string `json:"label"`
+	WarnPercent int    `json:"warn_percent"`
+}
+
+type cgroupMsg struct {
+	dir    string
+	sample cgroupSample
+}
+
+type CgroupModule struct {
+	ctx    *moduleContext
+	dir    string
+	last   cgroupSample
+	cpu    float64
+	ticks  int
+	loaded bool
+}
+
+func init() {
+	RegisterModule("cgroup", func(ctx *moduleContext) Module {
+		return &CgroupModule{ctx: ctx}
+	})
+}
+
+func (m *CgroupModule) Name() string {
+	return "cgroup"
+}
+
+func (m *CgroupModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+// fetch reads the cgroup, resolving it again when it's missing since
+// scopes come and go with the jobs they hold.
+func (m *CgroupModule) fetch() tea.Cmd {
+	name, dir := m.ctx.config.Cgroup.Path, m.dir
+	if name == "" {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		if dir == "" {
+			var ok bool
+			if dir, ok = resolveCgroup(name); !ok {
+				return cgroupMsg{}
+			}
+		}
+		sample := readCgroup(dir)
+		if !sample.present {
+			dir = ""
+		}
+		return cgroupMsg{dir: dir, sample: sample}
+	})
+}
+
+func (m *CgroupModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if m.dir != "" || m.ticks%10 == 0 {
+			return m.fetch()
+		}
+	case cgroupMsg:
+		m.dir = msg.dir
+		if m.last.present && msg.sample.present {
+			elapsed := msg.sample.at.Sub(m.last.at)
+			if elapsed > 0 {
+				m.cpu = 100 * float64(msg.sample.usage-m.last.usage) / float64(elapsed) / float64(runtime.NumCPU())
+			}
+		} else {
+			m.cpu = 0
+		}
+		m.last = msg.sample
+		m.loaded = true
+	}
+	return nil
+}
+
+func (m *CgroupModule) Ready() bool {
+	return m.loaded
+}
+
+func (m *CgroupModule) label() string {
+	if label := m.ctx.config.Cgroup.Label; label != "" {
+		return label
+	}
+	return m.ctx.config.Cgroup.Path
+}
+
+// memoryPercent is the share of memory.max in use, 0 without a limit.
+func (m *CgroupModule) memoryPercent() float64 {
+	if m.last.limit == 0 {
+		return 0
+	}
+	return 100 * float64(m.last.memory) / float64(m.last.limit)
+}
+
+func (m *CgroupModule) Vars(vars exprVars) {
+	vars["cgroup.present"] = m.last.present
+	vars["cgroup.cpu"] = m.cpu
+	vars["cgroup.memory"] = float64(m.last.memory)
+	vars["cgroup.memory_percent"] = m.memoryPercent()
+}
+
+func (m *CgroupModule) Describe() string {
+	if !m.last.present {
+		return ""
+	}
+	return fmt.Sprintf("%s cpu %.0f percent, memory %s", m.label(), m.cpu, m.ctx.units.bytes(m.last.memory))
+}
+
+func (m *CgroupModule) Render() string {
+	if !m.last.present {
+		return ""
+	}
+	text := fmt.Sprintf("%s %s %s%% %s", icon("󰒋"), truncate(m.label(), 16), m.ctx.locale.float(m.cpu, 0), m.ctx.units.bytes(m.last.memory))
+	if m.last.limit > 0 {
+		text += "/" + m.ctx.units.bytes(m.last.limit)
+	}
+	return text
+}
+
+func (m *CgroupModule) Style() lipgloss.Style {
+	if warn := m.ctx.config.Cgroup.WarnPercent; warn > 0 && m.memoryPercent() >= float64(warn) {
+		return warningStyle
+	}
+	return boxStyle
+}
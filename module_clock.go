@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type calendarDatesMsg []calendarDate
+
+// ClockModule opens a month calendar on left click, with the days from
+// the configured calendar files highlighted and named underneath.
+type ClockModule struct {
+	ctx   *moduleContext
+	now   time.Time
+	dates []calendarDate
+	month int // offset of the calendar from the current month
+}
+
+func init() {
+	RegisterModule("clock", func(ctx *moduleContext) Module {
+		return newClockModule(ctx)
+	})
+}
+
+func newClockModule(ctx *moduleContext) *ClockModule {
+	return &ClockModule{ctx: ctx, now: time.Now()}
+}
+
+func (m *ClockModule) Name() string {
+	return "clock"
+}
+
+func (m *ClockModule) Init() tea.Cmd {
+	return m.loadDates()
+}
+
+func (m *ClockModule) loadDates() tea.Cmd {
+	paths := m.ctx.config.Calendar.Dates
+	if len(paths) == 0 {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		return calendarDatesMsg(loadCalendarDates(paths))
+	})
+}
+
+func (m *ClockModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.now = time.Time(msg)
+	case calendarDatesMsg:
+		m.dates = msg
+	case popupMsg:
+		// pick up edits to the calendar files
+		if msg.open {
+			m.month = 0
+			return m.loadDates()
+		}
+	}
+	return nil
+}
+
+func (m *ClockModule) Describe() string {
+	return m.now.Format("15:04")
+}
+
+func (m *ClockModule) Render() string {
+	return m.ctx.locale.formatTime(m.now, "15:04;05 | Mon 02 Jan")
+}
+
+func (m *ClockModule) Style() lipgloss.Style {
+	return clockStyle
+}
+
+func (m *ClockModule) datesOn(t time.Time) []calendarDate {
+	var dates []calendarDate
+	for _, d := range m.dates {
+		if d.on(t) {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+func (m *ClockModule) Popup() []segments {
+	first := time.Date(m.now.Year(), m.now.Month()+time.Month(m.month), 1, 0, 0, 0, 0, time.Local)
+	days := first.AddDate(0, 1, -1).Day()
+
+	var title segments
+	title.add("prev", " ‹ ")
+	title.add("", padRight(m.ctx.locale.formatTime(first, "January 2006"), 16))
+	title.add("next", " › ")
+	rows := []segments{title}
+
+	// weeks start on Monday; 2024-01-01 was one
+	var header segments
+	for i := range 7 {
+		name := []rune(m.ctx.locale.formatTime(time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC), "Mon"))
+		header.add("", fmt.Sprintf(" %2s", string(name[:min(2, len(name))])))
+	}
+	header.add("", " ")
+	rows = append(rows, header)
+
+	var week segments
+	for range (int(first.Weekday()) + 6) % 7 {
+		week.add("", "   ")
+	}
+	var named []calendarDate
+	for day := 1; day <= days; day++ {
+		date := first.AddDate(0, 0, day-1)
+		cell := fmt.Sprintf(" %2d", day)
+		dates := m.datesOn(date)
+		switch {
+		case date.Year() == m.now.Year() && date.YearDay() == m.now.YearDay():
+			cell = popupSelectedStyle.Render(cell)
+		case len(dates) > 0:
+			cell = popupHighlightStyle.Render(cell)
+		}
+		named = append(named, dates...)
+		week.add("", cell)
+		if date.Weekday() == time.Sunday || day == days {
+			week.add("", " ")
+			rows = append(rows, week)
+			week = segments{}
+		}
+	}
+
+	slices.SortStableFunc(named, func(a, b calendarDate) int { return a.day - b.day })
+	for _, d := range named {
+		var row segments
+		row.add("", popupHighlightStyle.Render(fmt.Sprintf(" %2d", d.day))+" "+truncate(d.name, 30)+" ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (m *ClockModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	switch target {
+	case "prev":
+		if msg.Type == tea.MouseLeft {
+			m.month--
+		}
+		return nil
+	case "next":
+		if msg.Type == tea.MouseLeft {
+			m.month++
+		}
+		return nil
+	}
+	switch msg.Type {
+	case tea.MouseLeft:
+		return togglePopup(m.Name())
+	case tea.MouseWheelUp:
+		m.month--
+	case tea.MouseWheelDown:
+		m.month++
+	}
+	return nil
+}
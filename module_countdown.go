@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CountdownConfig lists upcoming events ("2026-07-01" or
+// "2026-07-01 09:30", local time). Several upcoming events rotate every
+// RotateSeconds; an event turns warning within WarnDays and critical
+// within CriticalDays.
+type CountdownConfig struct {
+	Events        []CountdownEvent `json:"events"`
+	RotateSeconds int              `json:"rotate_seconds"`
+	WarnDays      int              `json:"warn_days"`
+	CriticalDays  int              `json:"critical_days"`
+}
+
+type CountdownEvent struct {
+	Name string `json:"name"`
+	Date string `json:"date"`
+}
+
+type countdownEvent struct {
+	name string
+	at   time.Time
+}
+
+type CountdownModule struct {
+	ctx    *moduleContext
+	events []countdownEvent
+	now    time.Time
+	index  int
+	ticks  int
+}
+
+func init() {
+	RegisterModule("countdown", func(ctx *moduleContext) Module {
+		return newCountdownModule(ctx)
+	})
+}
+
+func newCountdownModule(ctx *moduleContext) *CountdownModule {
+	m := &CountdownModule{ctx: ctx, now: time.Now()}
+	for _, e := range ctx.config.Countdown.Events {
+		at, err := parseCountdownDate(e.Date)
+		if err != nil {
+			log.Printf("countdown: %s: %v", e.Name, err)
+			continue
+		}
+		m.events = append(m.events, countdownEvent{name: e.Name, at: at})
+	}
+	return m
+}
+
+func parseCountdownDate(date string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, date, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", date)
+}
+
+func (m *CountdownModule) Name() string {
+	return "countdown"
+}
+
+func (m *CountdownModule) Init() tea.Cmd {
+	return nil
+}
+
+func (m *CountdownModule) Update(msg tea.Msg) tea.Cmd {
+	if t, ok := msg.(tickMsg); ok {
+		m.now = time.Time(t)
+		m.ticks++
+		if rotate := m.ctx.config.Countdown.RotateSeconds; rotate > 0 && m.ticks%rotate == 0 {
+			m.index++
+		}
+	}
+	return nil
+}
+
+// upcoming drops events that have passed.
+func (m *CountdownModule) upcoming() []countdownEvent {
+	var events []countdownEvent
+	for _, e := range m.events {
+		if e.at.After(m.now) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func (m *CountdownModule) current() (countdownEvent, bool) {
+	events := m.upcoming()
+	if len(events) == 0 {
+		return countdownEvent{}, false
+	}
+	return events[m.index%len(events)], true
+}
+
+func (m *CountdownModule) Vars(vars exprVars) {
+	if e, ok := m.current(); ok {
+		vars["countdown.name"] = e.name
+		vars["countdown.days"] = int(e.at.Sub(m.now).Hours() / 24)
+	}
+}
+
+func (m *CountdownModule) Describe() string {
+	e, ok := m.current()
+	if !ok {
+		return ""
+	}
+	return e.name + " " + m.ctx.locale.text("in") + " " + countdownLeft(e.at.Sub(m.now))
+}
+
+// countdownLeft is whole days, or hours and then minutes on the last day.
+func countdownLeft(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes())+1)
+}
+
+func (m *CountdownModule) Render() string {
+	text := m.Describe()
+	if text == "" {
+		return ""
+	}
+	return icon("󰔟") + " " + text
+}
+
+func (m *CountdownModule) Style() lipgloss.Style {
+	e, ok := m.current()
+	if !ok {
+		return boxStyle
+	}
+	cfg := m.ctx.config.Countdown
+	days := e.at.Sub(m.now).Hours() / 24
+	switch {
+	case days < float64(cfg.CriticalDays):
+		return criticalStyle
+	case days < float64(cfg.WarnDays):
+		return warningStyle
+	}
+	return boxStyle
+}
+
+// HandleMouse: left click shows the next event.
+func (m *CountdownModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		m.index++
+		m.ticks = 0
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/metrics"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type debugToggleMsg struct{}
+
+// DebugModule is an overlay for diagnosing stalls: frame cost, goroutines,
+// heap, tick latency, dropped Hyprland events and the last collector
+// errors. It is always present but renders nothing until toggled on.
+type DebugModule struct {
+	ctx     *moduleContext
+	enabled bool
+
+	goroutines  int
+	heapBytes   uint64
+	tickLatency time.Duration
+}
+
+func init() {
+	RegisterModule("debug", func(ctx *moduleContext) Module {
+		return newDebugModule(ctx)
+	})
+}
+
+func newDebugModule(ctx *moduleContext) *DebugModule {
+	return &DebugModule{ctx: ctx}
+}
+
+func (m *DebugModule) Name() string {
+	return "debug"
+}
+
+func (m *DebugModule) Init() tea.Cmd {
+	return nil
+}
+
+var heapSample = []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+
+func (m *DebugModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case debugToggleMsg:
+		m.enabled = !m.enabled
+	case tickMsg:
+		if !m.enabled {
+			return nil
+		}
+		m.tickLatency = time.Since(time.Time(msg))
+		m.goroutines = runtime.NumGoroutine()
+		metrics.Read(heapSample)
+		m.heapBytes = heapSample[0].Value.Uint64()
+	}
+	return nil
+}
+
+func (m *DebugModule) SaveState() any {
+	return m.enabled
+}
+
+func (m *DebugModule) RestoreState(data json.RawMessage) {
+	json.Unmarshal(data, &m.enabled)
+}
+
+func (m *DebugModule) Vars(vars exprVars) {
+	s := m.ctx.frames.snapshot()
+	vars["debug.enabled"] = m.enabled
+	vars["debug.frame_ms"] = float64(s.avg.Microseconds()) / 1000
+	vars["debug.overrun"] = int(s.overrun)
+}
+
+func (m *DebugModule) Render() string {
+	if !m.enabled {
+		return ""
+	}
+
+	s := m.ctx.frames.snapshot()
+	parts := []string{
+		fmt.Sprintf("%s %.2fms max %.2fms %s/f %d over",
+			icon("󰔛"),
+			float64(s.avg.Microseconds())/1000,
+			float64(s.max.Microseconds())/1000,
+			m.ctx.units.bytes(s.allocBytes),
+			s.overrun),
+		fmt.Sprintf("%dg %s heap", m.goroutines, m.ctx.units.bytes(m.heapBytes)),
+		fmt.Sprintf("tick +%dms", m.tickLatency.Milliseconds()),
+	}
+	if m.ctx.hypr != nil {
+		parts = append(parts, m.ctx.locale.int(int64(m.ctx.hypr.DroppedEvents()))+" dropped")
+	}
+	if err := m.lastError(); err != "" {
+		parts = append(parts, err)
+	}
+	return strings.Join(parts, " │ ")
+}
+
+func (m *DebugModule) lastError() string {
+	var errs []string
+	for name, err := range m.ctx.metrics.Errors() {
+		errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+	}
+	for name, err := range m.ctx.faults.snapshot() {
+		errs = append(errs, fmt.Sprintf("%s: %s", name, err))
+	}
+	if len(errs) == 0 {
+		return ""
+	}
+	sort.Strings(errs)
+	return errs[0]
+}
+
+func (m *DebugModule) Style() lipgloss.Style {
+	if m.lastError() != "" || m.ctx.frames.snapshot().overrun > 0 {
+		return warningStyle
+	}
+	return boxStyle
+}
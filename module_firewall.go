@@ -0,0 +1,104 @@
+package main
+
+import (
+	"cmp"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type firewallMsg firewallState
+
+// firewallInterval is how many ticks pass between checks; firewalls don't
+// change often and the checks run systemctl.
+const firewallInterval = 60
+
+// FirewallModule shows whether ufw, firewalld or nftables is enforcing,
+// turning warning when none is. Left click checks again.
+type FirewallModule struct {
+	ctx   *moduleContext
+	state firewallState
+	ticks int
+	ready bool
+}
+
+func init() {
+	RegisterModule("firewall", func(ctx *moduleContext) Module {
+		return &FirewallModule{ctx: ctx}
+	})
+}
+
+func (m *FirewallModule) Name() string {
+	return "firewall"
+}
+
+func (m *FirewallModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *FirewallModule) fetch() tea.Cmd {
+	return moduleCmd(m.Name(), func() tea.Msg {
+		return firewallMsg(fetchFirewallState())
+	})
+}
+
+func (m *FirewallModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if m.ticks%firewallInterval == 0 {
+			return m.fetch()
+		}
+	case firewallMsg:
+		m.state = firewallState(msg)
+		m.ready = true
+	}
+	return nil
+}
+
+func (m *FirewallModule) Ready() bool {
+	return m.ready
+}
+
+func (m *FirewallModule) Vars(vars exprVars) {
+	vars["firewall.backend"] = m.state.backend
+	vars["firewall.active"] = m.state.active
+}
+
+func (m *FirewallModule) Describe() string {
+	switch {
+	case !m.ready:
+		return ""
+	case m.state.backend == "":
+		return m.ctx.locale.text("no firewall")
+	case !m.state.active:
+		return "firewall " + m.state.backend + " disabled"
+	}
+	return "firewall " + m.state.backend + " active"
+}
+
+func (m *FirewallModule) Render() string {
+	switch {
+	case !m.ready:
+		return placeholder("󰒃")
+	case !m.state.active:
+		return icon("󰦞") + " " + cmp.Or(m.state.backend, m.ctx.locale.text("none"))
+	case m.state.zone != "":
+		return icon("󰒃") + " " + m.state.zone
+	}
+	return icon("󰒃") + " " + m.state.backend
+}
+
+func (m *FirewallModule) Style() lipgloss.Style {
+	if m.ready && !m.state.active {
+		return warningStyle
+	}
+	return boxStyle
+}
+
+func (m *FirewallModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		return m.fetch()
+	}
+	return nil
+}
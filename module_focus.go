@@ -0,0 +1,55 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FocusModule is the on-bar switch for focus mode.
+type FocusModule struct {
+	ctx *moduleContext
+	on  bool
+}
+
+func init() {
+	RegisterModule("focus", func(ctx *moduleContext) Module {
+		return newFocusModule(ctx)
+	})
+}
+
+func newFocusModule(ctx *moduleContext) *FocusModule {
+	return &FocusModule{ctx: ctx}
+}
+
+func (m *FocusModule) Name() string {
+	return "focus"
+}
+
+func (m *FocusModule) Init() tea.Cmd {
+	return nil
+}
+
+func (m *FocusModule) Update(msg tea.Msg) tea.Cmd {
+	if on, ok := msg.(focusMsg); ok {
+		m.on = bool(on)
+	}
+	return nil
+}
+
+func (m *FocusModule) Render() string {
+	return icon("󰓾")
+}
+
+func (m *FocusModule) Style() lipgloss.Style {
+	if m.on {
+		return activeBoxStyle
+	}
+	return boxStyle
+}
+
+func (m *FocusModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		return toggleFocus()
+	}
+	return nil
+}
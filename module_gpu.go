@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type gpuMsg gpuState
+
+// gpuPopupRows caps the process list in the popup.
+const gpuPopupRows = 12
+
+// GPUModule shows GPU load and VRAM; left click opens a popup listing the
+// processes holding GPU memory.
+type GPUModule struct {
+	ctx   *moduleContext
+	state gpuState
+	ticks int
+	open  bool
+	ready bool
+}
+
+func init() {
+	RegisterModule("gpu", func(ctx *moduleContext) Module {
+		return &GPUModule{ctx: ctx, state: gpuState{busy: -1}}
+	})
+}
+
+func (m *GPUModule) Name() string {
+	return "gpu"
+}
+
+func (m *GPUModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *GPUModule) fetch() tea.Cmd {
+	withProcesses := m.open
+	return moduleCmd(m.Name(), func() tea.Msg {
+		return gpuMsg(fetchGPUState(withProcesses))
+	})
+}
+
+func (m *GPUModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		// nvidia-smi is too slow to run every second
+		if m.ticks%2 == 0 {
+			return m.fetch()
+		}
+	case gpuMsg:
+		m.state = gpuState(msg)
+		m.ready = true
+	case popupMsg:
+		m.open = msg.open
+		if m.open {
+			return m.fetch()
+		}
+	}
+	return nil
+}
+
+func (m *GPUModule) Ready() bool {
+	return m.ready
+}
+
+func (m *GPUModule) Vars(vars exprVars) {
+	vars["gpu.busy"] = m.state.busy
+	vars["gpu.vram"] = float64(m.state.vramUsed)
+}
+
+func (m *GPUModule) Describe() string {
+	if m.state.busy < 0 {
+		return ""
+	}
+	return fmt.Sprintf("GPU %d percent, %s video memory", m.state.busy, m.ctx.units.bytes(m.state.vramUsed))
+}
+
+func (m *GPUModule) Render() string {
+	if !m.ready {
+		return placeholder("󰢮")
+	}
+	if m.state.busy < 0 {
+		return ""
+	}
+	text := fmt.Sprintf("%s %d%%", icon("󰢮"), m.state.busy)
+	if m.state.vramTotal > 0 {
+		text += " " + m.ctx.units.bytes(m.state.vramUsed)
+	}
+	return text
+}
+
+func (m *GPUModule) Style() lipgloss.Style {
+	return boxStyle
+}
+
+func (m *GPUModule) Popup() []segments {
+	if len(m.state.processes) == 0 {
+		var s segments
+		s.add("", " "+m.ctx.locale.text("no GPU processes")+" ")
+		return []segments{s}
+	}
+
+	rows := make([]segments, 0, gpuPopupRows)
+	for _, p := range m.state.processes[:min(len(m.state.processes), gpuPopupRows)] {
+		var s segments
+		s.add("", fmt.Sprintf(" %s %7d %9s ", padRight(truncate(p.name, 16), 16), p.pid, m.ctx.units.bytes(p.vram)))
+		rows = append(rows, s)
+	}
+	return rows
+}
+
+func (m *GPUModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		return togglePopup(m.Name())
+	}
+	return nil
+}
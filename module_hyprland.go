@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HyprlandModule warns when running against a compositor version the
+// event and payload compatibility layer does not know about.
+type HyprlandModule struct {
+	ctx *moduleContext
+}
+
+func init() {
+	RegisterModule("hyprland", func(ctx *moduleContext) Module {
+		return newHyprlandModule(ctx)
+	})
+}
+
+func newHyprlandModule(ctx *moduleContext) *HyprlandModule {
+	return &HyprlandModule{ctx: ctx}
+}
+
+func (m *HyprlandModule) Name() string {
+	return "hyprland"
+}
+
+func (m *HyprlandModule) Init() tea.Cmd {
+	return nil
+}
+
+func (m *HyprlandModule) Update(msg tea.Msg) tea.Cmd {
+	return nil
+}
+
+func (m *HyprlandModule) Vars(vars exprVars) {
+	if m.ctx.hypr != nil {
+		vars["hyprland.version"] = m.ctx.hypr.Version().String()
+	}
+}
+
+func (m *HyprlandModule) Render() string {
+	if m.ctx.hypr == nil || m.ctx.hypr.Version().Known() {
+		return ""
+	}
+	return fmt.Sprintf(" hyprland %s", m.ctx.hypr.Version())
+}
+
+func (m *HyprlandModule) Style() lipgloss.Style {
+	return warningStyle
+}
+
+func hyprActionCmd(hc *HyprlandClient, action func() error, refresh tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		if err := action(); err != nil {
+			log.Printf("hyprland action failed: %v", err)
+		}
+		if refresh == nil {
+			return nil
+		}
+		return refresh()
+	}
+}
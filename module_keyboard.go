@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type lockKeysMsg lockKeys
+
+// KeyboardModule warns about Caps Lock (and optionally shows Num Lock).
+// It renders nothing while no shown lock is active.
+type KeyboardModule struct {
+	ctx  *moduleContext
+	keys lockKeys
+}
+
+func init() {
+	RegisterModule("keyboard", func(ctx *moduleContext) Module {
+		return newKeyboardModule(ctx)
+	})
+}
+
+func newKeyboardModule(ctx *moduleContext) *KeyboardModule {
+	return &KeyboardModule{ctx: ctx}
+}
+
+func (m *KeyboardModule) Name() string {
+	return "keyboard"
+}
+
+func (m *KeyboardModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *KeyboardModule) fetch() tea.Cmd {
+	hc := m.ctx.hypr
+	return moduleCmd(m.Name(), func() tea.Msg {
+		return lockKeysMsg(fetchLockKeys(hc))
+	})
+}
+
+func (m *KeyboardModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		return m.fetch()
+	case lockKeysMsg:
+		m.keys = lockKeys(msg)
+	}
+	return nil
+}
+
+func (m *KeyboardModule) Vars(vars exprVars) {
+	vars["keyboard.caps"] = m.keys.caps
+	vars["keyboard.num"] = m.keys.num
+}
+
+func (m *KeyboardModule) Render() string {
+	labels := m.keys.labels(m.ctx.config.Keyboard.ShowNumLock)
+	if len(labels) == 0 {
+		return ""
+	}
+	return icon("󰌌") + " " + strings.Join(labels, " ")
+}
+
+func (m *KeyboardModule) Style() lipgloss.Style {
+	if m.keys.caps {
+		return warningStyle
+	}
+	return boxStyle
+}
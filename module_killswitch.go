@@ -0,0 +1,106 @@
+package main
+
+import (
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type killSwitchMsg []killSwitch
+
+// KillSwitchModule shows whether the camera and microphone are disabled
+// at the hardware/policy level, as opposed to merely not in use.
+type KillSwitchModule struct {
+	ctx      *moduleContext
+	switches []killSwitch
+	ticks    int
+}
+
+func init() {
+	RegisterModule("killswitch", func(ctx *moduleContext) Module {
+		return newKillSwitchModule(ctx)
+	})
+}
+
+func newKillSwitchModule(ctx *moduleContext) *KillSwitchModule {
+	return &KillSwitchModule{ctx: ctx}
+}
+
+func (m *KillSwitchModule) Name() string {
+	return "killswitch"
+}
+
+func (m *KillSwitchModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *KillSwitchModule) fetch() tea.Cmd {
+	return moduleCmd(m.Name(), func() tea.Msg {
+		return killSwitchMsg(fetchKillSwitches())
+	})
+}
+
+func (m *KillSwitchModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if m.ticks%5 == 0 {
+			return m.fetch()
+		}
+	case killSwitchMsg:
+		m.switches = msg
+	}
+	return nil
+}
+
+func (m *KillSwitchModule) Vars(vars exprVars) {
+	for _, s := range m.switches {
+		vars["killswitch."+s.kind] = s.disabled
+	}
+}
+
+func (m *KillSwitchModule) Render() string {
+	return m.Segments().render()
+}
+
+func (m *KillSwitchModule) Segments() segments {
+	icons := map[string][2]string{
+		"camera": {"󰄀", "󰗟"},
+		"mic":    {"󰍬", "󰍭"},
+	}
+	var s segments
+	for _, sw := range m.switches {
+		if !sw.present {
+			continue
+		}
+		if sw.disabled {
+			s.add(sw.kind, goodStyle.Render(icon(icons[sw.kind][1])))
+		} else {
+			s.add(sw.kind, boxStyle.Render(icon(icons[sw.kind][0])))
+		}
+	}
+	return s
+}
+
+func (m *KillSwitchModule) Style() lipgloss.Style {
+	return boxStyle
+}
+
+func (m *KillSwitchModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type != tea.MouseLeft {
+		return nil
+	}
+	for _, sw := range m.switches {
+		if sw.kind != target || !sw.toggleable() {
+			continue
+		}
+		return moduleCmd(m.Name(), func() tea.Msg {
+			if err := sw.toggle(); err != nil {
+				log.Printf("killswitch: %s: %v", sw.kind, err)
+			}
+			return killSwitchMsg(fetchKillSwitches())
+		})
+	}
+	return nil
+}
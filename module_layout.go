@@ -0,0 +1,95 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type layoutMsg string
+
+type LayoutModule struct {
+	ctx    *moduleContext
+	layout string
+	ready  bool
+	ticks  int
+}
+
+func init() {
+	RegisterModule("layout", func(ctx *moduleContext) Module {
+		return newLayoutModule(ctx)
+	})
+}
+
+func newLayoutModule(ctx *moduleContext) *LayoutModule {
+	return &LayoutModule{ctx: ctx}
+}
+
+func (m *LayoutModule) Name() string {
+	return "layout"
+}
+
+func (m *LayoutModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *LayoutModule) fetch() tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		layout, _ := hc.GetLayout()
+		return layoutMsg(layout)
+	})
+}
+
+func (m *LayoutModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if hyprPoll(m.ctx.hypr, m.ticks) {
+			return m.fetch()
+		}
+	case hyprEventMsg:
+		if msg.Type == "configreloaded" {
+			return m.fetch()
+		}
+	case layoutMsg:
+		m.layout = string(msg)
+		m.ready = true
+	}
+	return nil
+}
+
+func (m *LayoutModule) Ready() bool {
+	return m.ready || m.ctx.hypr == nil
+}
+
+func (m *LayoutModule) Vars(vars exprVars) {
+	vars["layout"] = m.layout
+}
+
+func (m *LayoutModule) Render() string {
+	if !m.Ready() {
+		return placeholder("󰕮")
+	}
+	if m.layout == "" {
+		return ""
+	}
+	return getLayoutIcon(m.layout) + " " + m.layout
+}
+
+func (m *LayoutModule) Style() lipgloss.Style {
+	return layoutStyle
+}
+
+func (m *LayoutModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil || msg.Type != tea.MouseLeft {
+		return nil
+	}
+	next := nextLayout(m.layout)
+	return hyprActionCmd(hc, func() error {
+		return hc.SetLayout(next)
+	}, m.fetch())
+}
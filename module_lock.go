@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type lockTimeoutMsg time.Duration
+
+// LockModule launches the locker on click and counts down when an idle
+// lock is near, judged by the approximate activity tracking in idle.go.
+type LockModule struct {
+	ctx      *moduleContext
+	timeout  time.Duration
+	activity activity
+}
+
+func init() {
+	RegisterModule("lock", func(ctx *moduleContext) Module {
+		return newLockModule(ctx)
+	})
+}
+
+func newLockModule(ctx *moduleContext) *LockModule {
+	return &LockModule{ctx: ctx, activity: newActivity()}
+}
+
+func (m *LockModule) Name() string {
+	return "lock"
+}
+
+func (m *LockModule) Init() tea.Cmd {
+	configured := m.ctx.config.Lock.IdleTimeout
+	return moduleCmd(m.Name(), func() tea.Msg {
+		return lockTimeoutMsg(idleLockTimeout(configured))
+	})
+}
+
+func (m *LockModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case lockTimeoutMsg:
+		m.timeout = time.Duration(msg)
+	case tickMsg:
+		if m.timeout > 0 {
+			return fetchCursor(m.Name(), m.ctx.hypr)
+		}
+	default:
+		m.activity.observe(msg)
+	}
+	return nil
+}
+
+func (m *LockModule) remaining() time.Duration {
+	if m.timeout == 0 {
+		return -1
+	}
+	return max(m.timeout-m.activity.idle(), 0)
+}
+
+func (m *LockModule) imminent() bool {
+	warn := time.Duration(m.ctx.config.Lock.WarnSeconds) * time.Second
+	r := m.remaining()
+	return r >= 0 && r <= warn
+}
+
+func (m *LockModule) Vars(vars exprVars) {
+	vars["lock.imminent"] = m.imminent()
+	vars["lock.remaining"] = int(m.remaining().Seconds())
+}
+
+func (m *LockModule) Render() string {
+	if !m.imminent() {
+		return icon("󰌾")
+	}
+	r := m.remaining().Round(time.Second)
+	return fmt.Sprintf("%s %d:%02d", icon("󰌾"), int(r.Minutes()), int(r.Seconds())%60)
+}
+
+func (m *LockModule) Style() lipgloss.Style {
+	if m.imminent() {
+		return warningStyle
+	}
+	return boxStyle
+}
+
+func (m *LockModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	command := m.ctx.config.Lock.Command
+	if msg.Type != tea.MouseLeft || len(command) == 0 {
+		return nil
+	}
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		log.Printf("lock: %v", err)
+		return nil
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MediaConfig.MaxWidth is how many cells "artist – title" gets before it
+// scrolls. Scroll is "seek" to move through the track by SeekSeconds or
+// "track" to skip tracks.
+type MediaConfig struct {
+	MaxWidth    int    `json:"max_width"`
+	Scroll      string `json:"scroll"`
+	SeekSeconds int    `json:"seek_seconds"`
+}
+
+type mediaMsg []mediaPlayer
+type mprisChangedMsg struct{}
+
+// recent tracks shown in the popup
+const mediaPopupTracks = 10
+
+// MediaModule shows what the most relevant MPRIS player is playing,
+// following its signals rather than polling. Left click plays or pauses,
+// middle click skips, scrolling seeks (or skips, see MediaConfig) and
+// right click lists recently played tracks.
+type MediaModule struct {
+	ctx     *moduleContext
+	players []mediaPlayer
+	player  mediaPlayer
+	active  bool
+	history trackHistory
+	changed <-chan struct{}
+	offset  int
+	ready   bool
+}
+
+func init() {
+	RegisterModule("media", func(ctx *moduleContext) Module {
+		return &MediaModule{ctx: ctx}
+	})
+}
+
+func (m *MediaModule) Name() string {
+	return "media"
+}
+
+func (m *MediaModule) Init() tea.Cmd {
+	changed, err := watchMPRIS(m.ctx.lifetime)
+	if err != nil {
+		log.Printf("media: %v", err)
+		m.ready = true
+		return nil
+	}
+	m.changed = changed
+	return tea.Batch(m.fetch(), m.wait())
+}
+
+func (m *MediaModule) wait() tea.Cmd {
+	changed, done := m.changed, m.ctx.lifetime.Done()
+	return moduleCmd(m.Name(), func() tea.Msg {
+		select {
+		case <-changed:
+			return mprisChangedMsg{}
+		case <-done:
+			return nil
+		}
+	})
+}
+
+func (m *MediaModule) fetch() tea.Cmd {
+	return moduleCmd(m.Name(), func() tea.Msg {
+		players, err := fetchPlayers()
+		if err != nil {
+			log.Printf("media: %v", err)
+		}
+		return mediaMsg(players)
+	})
+}
+
+func (m *MediaModule) action(do func(bus string) error) tea.Cmd {
+	if !m.active {
+		return nil
+	}
+	bus := m.player.bus
+	return moduleCmd(m.Name(), func() tea.Msg {
+		if err := do(bus); err != nil {
+			log.Printf("media: %v", err)
+		}
+		return nil
+	})
+}
+
+func (m *MediaModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case mprisChangedMsg:
+		return tea.Batch(m.fetch(), m.wait())
+	case mediaMsg:
+		previous := m.player.track
+		m.players = msg
+		m.player, m.active = activePlayer(m.players)
+		m.ready = true
+		if !m.player.track.same(previous) {
+			m.offset = 0
+		}
+		switch {
+		case m.active && m.player.playing():
+			m.history.play(m.player.track, time.Now())
+		case !m.active || m.player.status == "Stopped":
+			m.history.play(playedTrack{}, time.Now())
+		}
+	case tickMsg:
+		if m.active && m.player.playing() {
+			m.player.position += time.Second
+			m.offset++
+		}
+	}
+	return nil
+}
+
+func (m *MediaModule) Ready() bool {
+	return m.ready
+}
+
+func (m *MediaModule) SaveState() any {
+	return m.history.save()
+}
+
+func (m *MediaModule) RestoreState(data json.RawMessage) {
+	m.history.restore(data)
+}
+
+func (m *MediaModule) Export() any {
+	export := map[string]any{"recent": m.history.save()}
+	if m.active {
+		export["now_playing"] = m.player.track
+		export["status"] = m.player.status
+	}
+	return export
+}
+
+func (m *MediaModule) Vars(vars exprVars) {
+	vars["media.status"] = m.player.status
+	vars["media.player"] = m.player.identity
+	vars["media.artist"] = m.player.track.Artist
+	vars["media.title"] = m.player.track.Title
+}
+
+func (m *MediaModule) Describe() string {
+	if !m.active || m.player.track.Title == "" {
+		return ""
+	}
+	text := m.player.track.Title
+	if m.player.track.Artist != "" {
+		text += " by " + m.player.track.Artist
+	}
+	if m.player.playing() {
+		return "playing " + text
+	}
+	return text + ", " + m.player.status
+}
+
+func mediaIcon(status string) string {
+	switch status {
+	case "Playing":
+		return icon("󰐊")
+	case "Paused":
+		return icon("󰏤")
+	}
+	return icon("󰓛")
+}
+
+func (m *MediaModule) Render() string {
+	if !m.ready {
+		return placeholder("󰐊")
+	}
+	if !m.active {
+		return ""
+	}
+	label := m.player.track.label()
+	if label == "" {
+		label = m.player.identity
+	}
+	return mediaIcon(m.player.status) + " " + marquee(label, m.ctx.config.Media.MaxWidth, m.offset)
+}
+
+func (m *MediaModule) Style() lipgloss.Style {
+	if m.active && !m.player.playing() {
+		return dimStyle
+	}
+	return boxStyle
+}
+
+func (m *MediaModule) Popup() []segments {
+	var rows []segments
+	if m.active {
+		var row segments
+		text := fmt.Sprintf(" %s %s", mediaIcon(m.player.status), m.player.identity)
+		if m.player.length > 0 {
+			text += fmt.Sprintf("  %s / %s", mediaDuration(m.player.position), mediaDuration(m.player.length))
+		}
+		row.add("", text+" ")
+		rows = append(rows, row)
+	}
+	for _, track := range m.history.recent(mediaPopupTracks) {
+		var row segments
+		row.add("", fmt.Sprintf(" %s %s ", track.Played.Local().Format("15:04"), truncate(track.label(), 40)))
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		var row segments
+		row.add("", " "+m.ctx.locale.text("nothing played yet")+" ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func mediaDuration(d time.Duration) string {
+	s := int(d.Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+func (m *MediaModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	config := m.ctx.config.Media
+	seek := time.Duration(config.SeekSeconds) * time.Second
+	switch msg.Type {
+	case tea.MouseLeft:
+		return m.action(mediaPlayPause)
+	case tea.MouseMiddle:
+		return m.action(mediaNext)
+	case tea.MouseRight:
+		return togglePopup(m.Name())
+	case tea.MouseWheelUp:
+		if config.Scroll == "track" {
+			return m.action(mediaPrevious)
+		}
+		if m.player.canSeek {
+			return m.action(func(bus string) error { return mediaSeek(bus, seek) })
+		}
+	case tea.MouseWheelDown:
+		if config.Scroll == "track" {
+			return m.action(mediaNext)
+		}
+		if m.player.canSeek {
+			return m.action(func(bus string) error { return mediaSeek(bus, -seek) })
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MetricModule shows a percentage served by the metrics collector
+type MetricModule struct {
+	ctx    *moduleContext
+	metric string
+	icon   string
+	value  float64
+	ready  bool
+}
+
+func init() {
+	RegisterModule("cpu", func(ctx *moduleContext) Module {
+		return newMetricModule(ctx, "cpu", "󰻠")
+	})
+	RegisterModule("memory", func(ctx *moduleContext) Module {
+		return newMetricModule(ctx, "memory", "󰍛")
+	})
+	RegisterModule("disk", func(ctx *moduleContext) Module {
+		return newMetricModule(ctx, "disk", "󰋊")
+	})
+}
+
+func newMetricModule(ctx *moduleContext, metric, icon string) *MetricModule {
+	return &MetricModule{
+		ctx:    ctx,
+		metric: metric,
+		icon:   icon,
+	}
+}
+
+func (m *MetricModule) Name() string {
+	return m.metric
+}
+
+func (m *MetricModule) Init() tea.Cmd {
+	m.refresh()
+	return nil
+}
+
+func (m *MetricModule) refresh() {
+	if value, ok := m.ctx.metrics.Latest(m.metric); ok {
+		m.value = value
+		m.ready = true
+	}
+}
+
+func (m *MetricModule) Update(msg tea.Msg) tea.Cmd {
+	if _, ok := msg.(tickMsg); ok {
+		m.refresh()
+	}
+	return nil
+}
+
+func (m *MetricModule) Ready() bool {
+	return m.ready
+}
+
+func (m *MetricModule) Vars(vars exprVars) {
+	vars[m.metric] = m.value
+}
+
+func (m *MetricModule) Describe() string {
+	if !m.ready {
+		return ""
+	}
+	return fmt.Sprintf("%s %.0f percent", m.metric, m.value)
+}
+
+func (m *MetricModule) Render() string {
+	if !m.ready {
+		return placeholder(m.icon)
+	}
+	text := fmt.Sprintf("%s %s%%", icon(m.icon), m.ctx.locale.float(m.value, 1))
+	if used, ok := m.ctx.metrics.Latest("memory.used"); ok && m.metric == "memory" && m.ctx.config.Metrics.MemoryUsed {
+		text = fmt.Sprintf("%s %s", icon(m.icon), m.ctx.units.bytes(uint64(used)))
+	}
+	if m.ctx.config.Metrics.Sparklines {
+		text += " " + sparkline(historyValues(m.ctx.metrics.History(m.metric)), 8, 100)
+	}
+	return text
+}
+
+// Style reads the shared style on every frame, so theme and
+// accessibility changes reach the module.
+func (m *MetricModule) Style() lipgloss.Style {
+	return *styleTargets[m.metric]
+}
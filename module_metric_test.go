@@ -0,0 +1,14 @@
+package main
+
+import "testing"
+
+func TestMetricStyleFollowsTheme(t *testing.T) {
+	defer applyTheme(builtinThemes["default"])
+	m := newMetricModule(&moduleContext{}, "cpu", "\U000F0EE0")
+	applyTheme(builtinThemes["dark"])
+	dark := m.Style().GetForeground()
+	applyTheme(builtinThemes["light"])
+	if light := m.Style().GetForeground(); light == dark {
+		t.Errorf("cpu foreground stayed %v after switching theme", light)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type networkMsg struct {
+	info     networkInfo
+	counters netCounters
+	ok       bool
+	at       time.Time
+	signal   int
+	wifi     bool
+}
+
+// signalHistory is how many seconds of WiFi signal the popup plots.
+const signalHistory = 40
+
+// the SSID is looked up again this often (in ticks), or when the
+// interface changes
+const ssidInterval = 15
+
+// NetworkModule shows the connection with its current throughput. Left
+// click opens a popup with totals since boot and since the bar started,
+// and on WiFi a sparkline of recent signal strength for finding dead spots.
+type NetworkModule struct {
+	ctx    *moduleContext
+	info   networkInfo
+	ready  bool
+	ticks  int
+	signal []int // dBm, oldest first
+
+	counters netCounters
+	start    netCounters
+	sampled  time.Time
+	rxRate   float64
+	txRate   float64
+}
+
+func init() {
+	RegisterModule("network", func(ctx *moduleContext) Module {
+		return newNetworkModule(ctx)
+	})
+}
+
+func newNetworkModule(ctx *moduleContext) *NetworkModule {
+	return &NetworkModule{
+		ctx:  ctx,
+		info: networkInfo{state: "disconnected"},
+	}
+}
+
+func (m *NetworkModule) Name() string {
+	return "network"
+}
+
+func (m *NetworkModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *NetworkModule) fetch() tea.Cmd {
+	withSSID := m.ticks%ssidInterval == 0
+	return moduleCmd(m.Name(), func() tea.Msg {
+		info := fetchNetworkInfo(withSSID)
+		counters, err := fetchNetCounters(info.name)
+		signal, wifi := readWifiSignal(info.name)
+		return networkMsg{
+			info:     info,
+			counters: counters,
+			ok:       err == nil,
+			at:       time.Now(),
+			signal:   signal,
+			wifi:     wifi && info.wireless,
+		}
+	})
+}
+
+func (m *NetworkModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		return m.fetch()
+	case networkMsg:
+		if msg.info.wireless && msg.info.ssid == "" {
+			if msg.info.name == m.info.name {
+				msg.info.ssid = m.info.ssid
+			} else {
+				m.ticks = -1 // new interface, look its SSID up next tick
+			}
+		}
+		if msg.info.name != m.info.name {
+			// another interface's counters: start over
+			m.sampled = time.Time{}
+			m.rxRate, m.txRate = 0, 0
+		}
+		m.info = msg.info
+		m.ready = true
+		if msg.ok {
+			m.sample(msg.counters, msg.at)
+		}
+		if msg.wifi {
+			m.signal = append(m.signal, msg.signal)
+			m.signal = m.signal[max(len(m.signal)-signalHistory, 0):]
+		} else {
+			m.signal = nil
+		}
+	}
+	return nil
+}
+
+func (m *NetworkModule) sample(c netCounters, at time.Time) {
+	if m.sampled.IsZero() {
+		m.start = c
+	} else if elapsed := at.Sub(m.sampled).Seconds(); elapsed > 0 {
+		// counters reset when an interface goes away
+		m.rxRate = float64(c.rx-min(c.rx, m.counters.rx)) / elapsed
+		m.txRate = float64(c.tx-min(c.tx, m.counters.tx)) / elapsed
+	}
+	m.start.rx = min(m.start.rx, c.rx)
+	m.start.tx = min(m.start.tx, c.tx)
+	m.counters = c
+	m.sampled = at
+}
+
+func (m *NetworkModule) Ready() bool {
+	return m.ready
+}
+
+func (m *NetworkModule) Vars(vars exprVars) {
+	vars["network.name"] = m.info.name
+	vars["network.state"] = m.info.state
+	vars["network.up"] = m.info.state == "connected"
+	vars["network.wireless"] = m.info.wireless
+	vars["network.ssid"] = m.info.ssid
+	vars["network.rx_rate"] = m.rxRate
+	vars["network.tx_rate"] = m.txRate
+	vars["network.session_rx"] = float64(m.counters.rx - m.start.rx)
+	vars["network.session_tx"] = float64(m.counters.tx - m.start.tx)
+	if len(m.signal) > 0 {
+		vars["network.signal"] = m.signal[len(m.signal)-1]
+	}
+}
+
+func (m *NetworkModule) Describe() string {
+	if !m.ready {
+		return ""
+	}
+	text := "network " + m.label() + " " + m.info.state
+	if len(m.signal) > 0 {
+		text += fmt.Sprintf(", signal %d dBm", m.signal[len(m.signal)-1])
+	}
+	switch m.ctx.network.Connectivity() {
+	case connectivityPortal:
+		text += ", captive portal"
+	case connectivityDNS:
+		text += ", DNS not resolving"
+	case connectivityLimited, connectivityNone:
+		text += ", no internet"
+	}
+	return text
+}
+
+// label is the WiFi network's name, or the interface's when there isn't
+// one.
+func (m *NetworkModule) label() string {
+	if m.info.ssid != "" {
+		return truncate(m.info.ssid, 20)
+	}
+	return m.info.name
+}
+
+func (m *NetworkModule) Render() string {
+	signal, wifi := 0, m.info.wireless
+	if len(m.signal) > 0 {
+		signal = m.signal[len(m.signal)-1]
+	}
+	netIcon := getNetworkIcon(m.info.state, m.ctx.network.Connectivity(), wifi, signal)
+	if !m.ready {
+		return placeholder(netIcon)
+	}
+	if m.info.state != "connected" {
+		return netIcon + m.ctx.locale.text("disconnected")
+	}
+	text := fmt.Sprintf("%s %s ↓%s ↑%s", netIcon, m.label(),
+		m.ctx.units.bytes(uint64(m.rxRate)), m.ctx.units.bytes(uint64(m.txRate)))
+	if m.ctx.network.Metered() {
+		text += " $"
+	}
+	return text
+}
+
+func (m *NetworkModule) Style() lipgloss.Style {
+	return networkStyle
+}
+
+func (m *NetworkModule) Popup() []segments {
+	rows := []struct {
+		label  string
+		rx, tx uint64
+	}{
+		{m.ctx.locale.text("since boot"), m.counters.rx, m.counters.tx},
+		{m.ctx.locale.text("since start"), m.counters.rx - m.start.rx, m.counters.tx - m.start.tx},
+	}
+	width := 0
+	for _, row := range rows {
+		width = max(width, textWidth(row.label))
+	}
+	out := make([]segments, len(rows))
+	for i, row := range rows {
+		out[i].add("", fmt.Sprintf(" %s ↓%9s ↑%9s ", padRight(row.label, width), m.ctx.units.bytes(row.rx), m.ctx.units.bytes(row.tx)))
+	}
+	if len(m.signal) > 0 {
+		var s segments
+		s.add("", fmt.Sprintf(" %s %d dBm ", signalSparkline(m.signal), m.signal[len(m.signal)-1]))
+		out = append(out, s)
+	}
+	return out
+}
+
+// signalSparkline plots dBm from -90 (unusable) to -30 (next to the
+// access point).
+func signalSparkline(signal []int) string {
+	values := make([]float64, len(signal))
+	for i, dbm := range signal {
+		values[i] = float64(dbm + 90)
+	}
+	return sparkline(values, signalHistory, 60)
+}
+
+func (m *NetworkModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		return togglePopup(m.Name())
+	}
+	return nil
+}
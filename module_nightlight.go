@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type nightLightMsg nightLightStatus
+
+type NightLightModule struct {
+	ctx    *moduleContext
+	status nightLightStatus
+	ticks  int
+	ready  bool
+}
+
+func init() {
+	RegisterModule("nightlight", func(ctx *moduleContext) Module {
+		return newNightLightModule(ctx)
+	})
+}
+
+func newNightLightModule(ctx *moduleContext) *NightLightModule {
+	return &NightLightModule{ctx: ctx}
+}
+
+func (m *NightLightModule) Name() string {
+	return "nightlight"
+}
+
+func (m *NightLightModule) backend() string {
+	if b := m.ctx.config.NightLight.Backend; b != "" {
+		return b
+	}
+	return "hyprsunset"
+}
+
+func (m *NightLightModule) temperature() int {
+	if m.status.temperature > 0 {
+		return m.status.temperature
+	}
+	if t := m.ctx.config.NightLight.Temperature; t > 0 {
+		return t
+	}
+	return 4500
+}
+
+func (m *NightLightModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *NightLightModule) fetch() tea.Cmd {
+	backend := m.backend()
+	return moduleCmd(m.Name(), func() tea.Msg {
+		return nightLightMsg(nightLightState(backend))
+	})
+}
+
+func (m *NightLightModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if m.ticks%5 == 0 {
+			return m.fetch()
+		}
+	case nightLightMsg:
+		m.status = nightLightStatus(msg)
+		m.ready = true
+	}
+	return nil
+}
+
+func (m *NightLightModule) Ready() bool {
+	return m.ready
+}
+
+func (m *NightLightModule) Vars(vars exprVars) {
+	vars["nightlight.active"] = m.status.active
+	vars["nightlight.temperature"] = m.status.temperature
+}
+
+func (m *NightLightModule) Render() string {
+	if !m.ready {
+		return placeholder("󰖔")
+	}
+	if !m.status.active {
+		return icon("󰖙")
+	}
+	if m.ctx.config.NightLight.ShowTemperature && m.status.temperature > 0 {
+		return fmt.Sprintf("%s %dK", icon("󰖔"), m.status.temperature)
+	}
+	return icon("󰖔")
+}
+
+func (m *NightLightModule) Style() lipgloss.Style {
+	if m.status.active {
+		return activeBoxStyle
+	}
+	return boxStyle
+}
+
+func (m *NightLightModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	backend, temperature, active := m.backend(), m.temperature(), m.status.active
+
+	var action func() error
+	switch msg.Type {
+	case tea.MouseLeft:
+		action = func() error {
+			if active {
+				return stopNightLight(backend)
+			}
+			return startNightLight(backend, temperature)
+		}
+	case tea.MouseWheelUp, tea.MouseWheelDown:
+		if !active {
+			return nil
+		}
+		step := nightLightStep
+		if msg.Type == tea.MouseWheelDown {
+			step = -step
+		}
+		temperature = min(max(temperature+step, nightLightMin), nightLightMax)
+		m.status.temperature = temperature
+		action = func() error {
+			return setNightLightTemperature(backend, temperature)
+		}
+	default:
+		return nil
+	}
+
+	return moduleCmd(m.Name(), func() tea.Msg {
+		if err := action(); err != nil {
+			log.Printf("nightlight: %v", err)
+		}
+		return nightLightMsg(nightLightState(backend))
+	})
+}
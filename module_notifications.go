@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type notificationsMsg []notification
+type notificationArrivedMsg notification
+
+// NotificationsModule counts notifications in the daemon's history. Left
+// click opens the recent ones: clicking an entry invokes its default
+// action and 󰅖 dismisses it. Right click clears everything. With the
+// builtin backend new notifications also show on the bar for a while.
+type NotificationsModule struct {
+	ctx           *moduleContext
+	backend       string
+	daemon        *notificationDaemon
+	notifications []notification
+	transient     *notification
+	until         time.Time
+	ticks         int
+	ready         bool
+}
+
+func init() {
+	RegisterModule("notifications", func(ctx *moduleContext) Module {
+		return newNotificationsModule(ctx)
+	})
+}
+
+func newNotificationsModule(ctx *moduleContext) *NotificationsModule {
+	return &NotificationsModule{ctx: ctx}
+}
+
+func (m *NotificationsModule) Name() string {
+	return "notifications"
+}
+
+func (m *NotificationsModule) Init() tea.Cmd {
+	m.backend = notificationBackend(m.ctx.config.Notifications.Backend)
+	if m.backend == "builtin" {
+		daemon, err := startNotificationDaemon(m.ctx.lifetime)
+		if err != nil {
+			log.Printf("notification daemon: %v", err)
+		}
+		m.daemon = daemon
+		return tea.Batch(m.fetch(), m.wait())
+	}
+	return m.fetch()
+}
+
+func (m *NotificationsModule) wait() tea.Cmd {
+	if m.daemon == nil {
+		return nil
+	}
+	arrivals, done := m.daemon.arrivals, m.ctx.lifetime.Done()
+	return moduleCmd(m.Name(), func() tea.Msg {
+		select {
+		case n := <-arrivals:
+			return notificationArrivedMsg(n)
+		case <-done:
+			return nil
+		}
+	})
+}
+
+func (m *NotificationsModule) fetch() tea.Cmd {
+	if m.backend == "builtin" {
+		daemon := m.daemon
+		return moduleCmd(m.Name(), func() tea.Msg {
+			if daemon == nil {
+				return notificationsMsg(nil)
+			}
+			return notificationsMsg(daemon.list())
+		})
+	}
+	backend := m.backend
+	return moduleCmd(m.Name(), func() tea.Msg {
+		notifications, err := fetchNotifications(backend)
+		if err != nil {
+			// no daemon yet; show an empty history rather than a spinner
+			return notificationsMsg(nil)
+		}
+		return notificationsMsg(notifications)
+	})
+}
+
+func (m *NotificationsModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if m.transient != nil && !m.until.IsZero() && time.Time(msg).After(m.until) {
+			m.daemon.expire(m.transient.id)
+			m.transient = nil
+		}
+		if m.ticks%5 == 0 {
+			return m.fetch()
+		}
+	case notificationArrivedMsg:
+		n := notification(msg)
+		m.transient = &n
+		m.until = time.Time{}
+		// critical notifications stay until dealt with
+		if n.urgency < 2 {
+			timeout := n.timeout
+			if timeout <= 0 {
+				timeout = time.Duration(max(m.ctx.config.Notifications.TransientSeconds, 1)) * time.Second
+			}
+			m.until = n.at.Add(timeout)
+		}
+		return tea.Batch(m.fetch(), m.wait())
+	case popupMsg:
+		if msg.open {
+			return m.fetch()
+		}
+	case notificationsMsg:
+		m.notifications = msg
+		m.ready = true
+	}
+	return nil
+}
+
+func (m *NotificationsModule) Ready() bool {
+	return m.ready
+}
+
+func (m *NotificationsModule) Vars(vars exprVars) {
+	vars["notifications.count"] = len(m.notifications)
+}
+
+func (m *NotificationsModule) Render() string {
+	if n := m.transient; n != nil {
+		return icon("󰂚") + " " + truncate(n.app+": "+n.summary, 50)
+	}
+	if len(m.notifications) == 0 {
+		return icon("󰂚")
+	}
+	return fmt.Sprintf("%s %s", icon("󰂚"), m.ctx.locale.int(int64(len(m.notifications))))
+}
+
+func (m *NotificationsModule) Style() lipgloss.Style {
+	if m.transient != nil {
+		if m.transient.urgency >= 2 {
+			return criticalStyle
+		}
+		return warningStyle
+	}
+	if len(m.notifications) > 0 {
+		return activeBoxStyle
+	}
+	return boxStyle
+}
+
+func (m *NotificationsModule) Popup() []segments {
+	limit := m.ctx.config.Notifications.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if len(m.notifications) == 0 {
+		var s segments
+		s.add("", " "+m.ctx.locale.text("no notifications")+" ")
+		return []segments{s}
+	}
+
+	var rows []segments
+	for _, n := range m.notifications[:min(limit, len(m.notifications))] {
+		text := truncate(n.app, 12) + ": " + n.summary
+		if n.body != "" {
+			text += " — " + strings.ReplaceAll(n.body, "\n", " ")
+		}
+		var row segments
+		row.add(fmt.Sprintf("invoke:%d", n.id), " "+padRight(truncate(text, 60), 60))
+		row.add(fmt.Sprintf("dismiss:%d", n.id), " "+icon("󰅖")+" ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (m *NotificationsModule) find(id string) (notification, bool) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return notification{}, false
+	}
+	for _, candidate := range m.notifications {
+		if candidate.id == n {
+			return candidate, true
+		}
+	}
+	return notification{}, false
+}
+
+func (m *NotificationsModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if kind, id, ok := strings.Cut(target, ":"); ok {
+		n, found := m.find(id)
+		if !found || msg.Type != tea.MouseLeft {
+			return nil
+		}
+		switch kind {
+		case "invoke":
+			return tea.Batch(closePopup(), m.action(func() error { return m.invoke(n) }))
+		case "dismiss":
+			return m.action(func() error { return m.dismiss(n) })
+		}
+		return nil
+	}
+
+	switch msg.Type {
+	case tea.MouseLeft:
+		if n := m.transient; n != nil {
+			m.transient = nil
+			return m.action(func() error { return m.invoke(*n) })
+		}
+		return togglePopup(m.Name())
+	case tea.MouseRight:
+		m.transient = nil
+		return m.action(m.clear)
+	}
+	return nil
+}
+
+func (m *NotificationsModule) invoke(n notification) error {
+	if m.daemon != nil {
+		return m.daemon.invoke(n)
+	}
+	return invokeNotification(m.backend, n)
+}
+
+func (m *NotificationsModule) dismiss(n notification) error {
+	if m.daemon != nil {
+		m.daemon.remove(n.id, notifyDismissed)
+		return nil
+	}
+	return dismissNotification(m.backend, n)
+}
+
+func (m *NotificationsModule) clear() error {
+	if m.daemon != nil {
+		m.daemon.clear()
+		return nil
+	}
+	return clearNotifications(m.backend)
+}
+
+func (m *NotificationsModule) action(run func() error) tea.Cmd {
+	refresh := m.fetch()
+	return func() tea.Msg {
+		if err := run(); err != nil {
+			log.Printf("notifications: %v", err)
+		}
+		return refresh()
+	}
+}
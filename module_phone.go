@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PhoneConfig picks the KDE Connect device by name or id; empty means the
+// first reachable one.
+type PhoneConfig struct {
+	Device string `json:"device"`
+}
+
+type phoneMsg struct {
+	phone phoneState
+	ok    bool
+}
+
+// PhoneModule shows a paired phone's battery and pending notifications
+// through KDE Connect. Left click opens a popup to ring it or send it the
+// clipboard.
+type PhoneModule struct {
+	ctx   *moduleContext
+	phone phoneState
+	found bool
+	ticks int
+	open  bool
+}
+
+func init() {
+	RegisterModule("phone", func(ctx *moduleContext) Module {
+		return &PhoneModule{ctx: ctx}
+	})
+}
+
+func (m *PhoneModule) Name() string {
+	return "phone"
+}
+
+func (m *PhoneModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *PhoneModule) fetch() tea.Cmd {
+	want := m.ctx.config.Phone.Device
+	return moduleCmd(m.Name(), func() tea.Msg {
+		phone, err := fetchPhone(want)
+		return phoneMsg{phone: phone, ok: err == nil}
+	})
+}
+
+func (m *PhoneModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if m.ticks%10 == 0 {
+			return m.fetch()
+		}
+	case phoneMsg:
+		m.phone, m.found = msg.phone, msg.ok
+		if m.open && !m.found {
+			return closePopup()
+		}
+	case popupMsg:
+		m.open = msg.open
+	}
+	return nil
+}
+
+func (m *PhoneModule) Vars(vars exprVars) {
+	vars["phone.connected"] = m.found
+	if m.found {
+		vars["phone.charge"] = m.phone.charge
+		vars["phone.notifications"] = m.phone.notifications
+	}
+}
+
+func (m *PhoneModule) Describe() string {
+	if !m.found {
+		return ""
+	}
+	text := m.phone.name
+	if m.phone.charge >= 0 {
+		text += fmt.Sprintf(" battery %d percent", m.phone.charge)
+	}
+	if m.phone.notifications > 0 {
+		text += fmt.Sprintf(", %d notifications", m.phone.notifications)
+	}
+	return text
+}
+
+func (m *PhoneModule) Render() string {
+	if !m.found {
+		return ""
+	}
+	text := icon("󰄜")
+	if m.phone.charge >= 0 {
+		text += fmt.Sprintf(" %d%%", m.phone.charge)
+		if m.phone.charging {
+			text += "+"
+		}
+	}
+	if m.phone.notifications > 0 {
+		text += fmt.Sprintf(" %s %d", icon("󰂚"), m.phone.notifications)
+	}
+	return text
+}
+
+func (m *PhoneModule) Style() lipgloss.Style {
+	if m.found && m.phone.charge >= 0 && m.phone.charge < 20 && !m.phone.charging {
+		return warningStyle
+	}
+	return boxStyle
+}
+
+func (m *PhoneModule) Popup() []segments {
+	var title segments
+	title.add("", " "+truncate(m.phone.name, 30)+" ")
+
+	var actions segments
+	actions.add("action:ring", " "+icon("󰓃")+" ring ")
+	actions.add("action:clipboard", " "+icon("󰅇")+" send clipboard ")
+	return []segments{title, actions}
+}
+
+func (m *PhoneModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type != tea.MouseLeft {
+		return nil
+	}
+	if action, ok := strings.CutPrefix(target, "action:"); ok {
+		id := m.phone.id
+		do := ringPhone
+		if action == "clipboard" {
+			do = sendClipboardToPhone
+		}
+		return tea.Batch(closePopup(), moduleCmd(m.Name(), func() tea.Msg {
+			if err := do(id); err != nil {
+				log.Printf("phone: %s: %v", action, err)
+			}
+			return nil
+		}))
+	}
+	if m.found {
+		return togglePopup(m.Name())
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type printJobsMsg []printJob
+
+// PrinterModule appears while CUPS has unfinished jobs, warning when one
+// is stuck. Left click opens a popup of the queue where each job can be
+// cancelled.
+type PrinterModule struct {
+	ctx   *moduleContext
+	jobs  []printJob
+	now   time.Time
+	ticks int
+	open  bool
+}
+
+func init() {
+	RegisterModule("printer", func(ctx *moduleContext) Module {
+		return &PrinterModule{ctx: ctx, now: time.Now()}
+	})
+}
+
+func (m *PrinterModule) Name() string {
+	return "printer"
+}
+
+func (m *PrinterModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *PrinterModule) fetch() tea.Cmd {
+	return moduleCmd(m.Name(), func() tea.Msg {
+		jobs, err := fetchPrintJobs()
+		if err != nil {
+			// no CUPS is the common case, not worth a log line
+			return printJobsMsg(nil)
+		}
+		return printJobsMsg(jobs)
+	})
+}
+
+func (m *PrinterModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.now = time.Time(msg)
+		m.ticks++
+		if m.ticks%5 == 0 {
+			return m.fetch()
+		}
+	case printJobsMsg:
+		m.jobs = msg
+		if m.open && len(m.jobs) == 0 {
+			return closePopup()
+		}
+	case popupMsg:
+		m.open = msg.open
+	}
+	return nil
+}
+
+func (m *PrinterModule) stuck() int {
+	n := 0
+	for _, job := range m.jobs {
+		if job.stuck(m.now) {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *PrinterModule) Vars(vars exprVars) {
+	vars["printer.jobs"] = len(m.jobs)
+	vars["printer.stuck"] = m.stuck()
+}
+
+func (m *PrinterModule) Describe() string {
+	if len(m.jobs) == 0 {
+		return ""
+	}
+	text := fmt.Sprintf("%d print jobs", len(m.jobs))
+	if stuck := m.stuck(); stuck > 0 {
+		text += fmt.Sprintf(", %d stuck", stuck)
+	}
+	return text
+}
+
+func (m *PrinterModule) Render() string {
+	if len(m.jobs) == 0 {
+		return ""
+	}
+	text := fmt.Sprintf("%s %d", icon("󰐪"), len(m.jobs))
+	if m.stuck() > 0 {
+		text += " !"
+	}
+	return text
+}
+
+func (m *PrinterModule) Style() lipgloss.Style {
+	if m.stuck() > 0 {
+		return warningStyle
+	}
+	return boxStyle
+}
+
+func (m *PrinterModule) Popup() []segments {
+	rows := make([]segments, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		state := job.stateName()
+		if job.stuck(m.now) && len(job.reasons) > 0 && job.reasons[0] != "none" {
+			state = job.reasons[0]
+		}
+		var row segments
+		row.add("", fmt.Sprintf(" %5d %s %s %s", job.id, padRight(truncate(job.name, 24), 24), padRight(truncate(job.printer, 12), 12), padRight(truncate(state, 20), 20)))
+		row.add("cancel:"+strconv.Itoa(job.id), " "+icon("󰅖")+" ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (m *PrinterModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type != tea.MouseLeft {
+		return nil
+	}
+	if id, ok := strings.CutPrefix(target, "cancel:"); ok {
+		job, err := strconv.Atoi(id)
+		if err != nil {
+			return nil
+		}
+		return moduleCmd(m.Name(), func() tea.Msg {
+			if err := cancelPrintJob(job); err != nil {
+				log.Printf("printer: cancel job %d: %v", job, err)
+			}
+			jobs, _ := fetchPrintJobs()
+			return printJobsMsg(jobs)
+		})
+	}
+	if len(m.jobs) > 0 {
+		return togglePopup(m.Name())
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type removableMsg []removableDrive
+type udisksChangedMsg struct{}
+
+// RemovableModule appears while removable drives are mounted. Left click
+// opens a popup listing them, each with a safe-eject button that unmounts
+// and powers the drive off.
+type RemovableModule struct {
+	ctx     *moduleContext
+	drives  []removableDrive
+	changed <-chan struct{}
+	open    bool
+}
+
+func init() {
+	RegisterModule("removable", func(ctx *moduleContext) Module {
+		return &RemovableModule{ctx: ctx}
+	})
+}
+
+func (m *RemovableModule) Name() string {
+	return "removable"
+}
+
+func (m *RemovableModule) Init() tea.Cmd {
+	changed, err := watchUDisks(m.ctx.lifetime)
+	if err != nil {
+		log.Printf("removable: %v", err)
+		return nil
+	}
+	m.changed = changed
+	return tea.Batch(m.fetch(), m.wait())
+}
+
+func (m *RemovableModule) wait() tea.Cmd {
+	changed, done := m.changed, m.ctx.lifetime.Done()
+	return moduleCmd(m.Name(), func() tea.Msg {
+		select {
+		case <-changed:
+			return udisksChangedMsg{}
+		case <-done:
+			return nil
+		}
+	})
+}
+
+func (m *RemovableModule) fetch() tea.Cmd {
+	return moduleCmd(m.Name(), func() tea.Msg {
+		drives, err := fetchRemovableDrives()
+		if err != nil {
+			log.Printf("removable: %v", err)
+		}
+		return removableMsg(drives)
+	})
+}
+
+func (m *RemovableModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case udisksChangedMsg:
+		return tea.Batch(m.fetch(), m.wait())
+	case removableMsg:
+		m.drives = msg
+		if m.open && len(m.drives) == 0 {
+			return closePopup()
+		}
+	case popupMsg:
+		m.open = msg.open
+	}
+	return nil
+}
+
+func (m *RemovableModule) Vars(vars exprVars) {
+	vars["removable.drives"] = len(m.drives)
+}
+
+func (m *RemovableModule) Describe() string {
+	if len(m.drives) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d removable drives mounted", len(m.drives))
+}
+
+func (m *RemovableModule) Render() string {
+	if len(m.drives) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s %d", icon("󰕓"), len(m.drives))
+}
+
+func (m *RemovableModule) Style() lipgloss.Style {
+	return boxStyle
+}
+
+func (m *RemovableModule) Popup() []segments {
+	rows := make([]segments, 0, len(m.drives))
+	for _, d := range m.drives {
+		var row segments
+		row.add("", fmt.Sprintf(" %s %s", padRight(truncate(d.name, 20), 20), padRight(truncate(strings.Join(d.mounts, " "), 30), 30)))
+		row.add("eject:"+d.id(), " "+icon("󰕔")+" ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (m *RemovableModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type != tea.MouseLeft {
+		return nil
+	}
+	if id, ok := strings.CutPrefix(target, "eject:"); ok {
+		for _, d := range m.drives {
+			if d.id() == id {
+				return moduleCmd(m.Name(), func() tea.Msg {
+					if err := ejectDrive(d); err != nil {
+						log.Printf("removable: eject %s: %v", d.name, err)
+					}
+					// the watcher reports the change
+					return nil
+				})
+			}
+		}
+		return nil
+	}
+	if len(m.drives) > 0 {
+		return togglePopup(m.Name())
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type scheduleEntry struct {
+	ScheduleEntry
+	specs []*cronSpec
+	next  time.Time
+}
+
+// ScheduleModule shows the next entry of a recurring timetable (prayer
+// times, stand-ups, medication) and runs the entry's command when it
+// comes round.
+type ScheduleModule struct {
+	ctx     *moduleContext
+	entries []*scheduleEntry
+	now     time.Time
+}
+
+func init() {
+	RegisterModule("schedule", func(ctx *moduleContext) Module {
+		return newScheduleModule(ctx)
+	})
+}
+
+func newScheduleModule(ctx *moduleContext) *ScheduleModule {
+	m := &ScheduleModule{ctx: ctx, now: time.Now()}
+	for _, e := range ctx.config.Schedule.Entries {
+		specs, err := scheduleSpecs(e)
+		if err != nil {
+			log.Printf("schedule: %s: %v", e.Name, err)
+			continue
+		}
+		entry := &scheduleEntry{ScheduleEntry: e, specs: specs}
+		entry.advance(m.now)
+		m.entries = append(m.entries, entry)
+	}
+	return m
+}
+
+func (e *scheduleEntry) advance(after time.Time) {
+	e.next = time.Time{}
+	for _, c := range e.specs {
+		if t, ok := c.next(after); ok && (e.next.IsZero() || t.Before(e.next)) {
+			e.next = t
+		}
+	}
+}
+
+func (m *ScheduleModule) Name() string {
+	return "schedule"
+}
+
+func (m *ScheduleModule) Init() tea.Cmd {
+	return nil
+}
+
+func (m *ScheduleModule) Update(msg tea.Msg) tea.Cmd {
+	t, ok := msg.(tickMsg)
+	if !ok {
+		return nil
+	}
+	m.now = time.Time(t)
+	for _, e := range m.entries {
+		if e.next.IsZero() || m.now.Before(e.next) {
+			continue
+		}
+		// don't replay everything missed during a suspend
+		if m.now.Sub(e.next) < 5*time.Minute {
+			e.run()
+		}
+		e.advance(m.now)
+	}
+	return nil
+}
+
+func (e *scheduleEntry) run() {
+	if len(e.Command) == 0 {
+		return
+	}
+	cmd := exec.Command(e.Command[0], e.Command[1:]...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		log.Printf("schedule: %s: %v", e.Name, err)
+		return
+	}
+	go cmd.Wait()
+}
+
+func (m *ScheduleModule) upcoming() (*scheduleEntry, bool) {
+	var next *scheduleEntry
+	for _, e := range m.entries {
+		if !e.next.IsZero() && (next == nil || e.next.Before(next.next)) {
+			next = e
+		}
+	}
+	return next, next != nil
+}
+
+func (m *ScheduleModule) Vars(vars exprVars) {
+	if e, ok := m.upcoming(); ok {
+		vars["schedule.next"] = e.Name
+		vars["schedule.remaining"] = int(e.next.Sub(m.now).Seconds())
+	}
+}
+
+func (m *ScheduleModule) Describe() string {
+	if e, ok := m.upcoming(); ok {
+		return e.Name + " " + e.next.Format("15:04")
+	}
+	return ""
+}
+
+func (m *ScheduleModule) Render() string {
+	e, ok := m.upcoming()
+	if !ok {
+		return ""
+	}
+	text := fmt.Sprintf("%s %s %s", icon("󰥔"), e.Name, e.next.Format("15:04"))
+	if left := e.next.Sub(m.now); left < time.Hour {
+		text += " (" + shortDuration(max(left, time.Minute)) + ")"
+	}
+	return text
+}
+
+func (m *ScheduleModule) Style() lipgloss.Style {
+	if e, ok := m.upcoming(); ok && e.next.Sub(m.now) < 10*time.Minute {
+		return warningStyle
+	}
+	return boxStyle
+}
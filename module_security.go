@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SecurityConfig: the module appears once more than Threshold SSH or sudo
+// logins have failed within the last WindowMinutes.
+type SecurityConfig struct {
+	WindowMinutes int `json:"window_minutes"`
+	Threshold     int `json:"threshold"`
+}
+
+type failedLoginsMsg failedLogins
+
+// securityInterval is how many ticks pass between journal reads.
+const securityInterval = 60
+
+type SecurityModule struct {
+	ctx    *moduleContext
+	failed failedLogins
+	ticks  int
+	logged bool
+}
+
+func init() {
+	RegisterModule("security", func(ctx *moduleContext) Module {
+		return &SecurityModule{ctx: ctx}
+	})
+}
+
+func (m *SecurityModule) Name() string {
+	return "security"
+}
+
+func (m *SecurityModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *SecurityModule) fetch() tea.Cmd {
+	window := time.Duration(m.ctx.config.Security.WindowMinutes) * time.Minute
+	// only the first failure is worth a log line; it'll be permissions
+	quiet := m.logged
+	m.logged = true
+	return moduleCmd(m.Name(), func() tea.Msg {
+		failed, err := fetchFailedLogins(window)
+		if err != nil {
+			if !quiet {
+				log.Printf("security: journalctl: %v", err)
+			}
+			return nil
+		}
+		return failedLoginsMsg(failed)
+	})
+}
+
+func (m *SecurityModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if m.ticks%securityInterval == 0 {
+			return m.fetch()
+		}
+	case failedLoginsMsg:
+		m.failed = failedLogins(msg)
+	}
+	return nil
+}
+
+func (m *SecurityModule) alerting() bool {
+	return m.failed.total() > m.ctx.config.Security.Threshold
+}
+
+func (m *SecurityModule) Vars(vars exprVars) {
+	vars["security.failed_ssh"] = m.failed.ssh
+	vars["security.failed_sudo"] = m.failed.sudo
+	vars["security.failed_logins"] = m.failed.total()
+}
+
+func (m *SecurityModule) Describe() string {
+	if !m.alerting() {
+		return ""
+	}
+	return fmt.Sprintf("%d failed logins in the last %d minutes", m.failed.total(), m.ctx.config.Security.WindowMinutes)
+}
+
+func (m *SecurityModule) Render() string {
+	if !m.alerting() {
+		return ""
+	}
+	text := icon("󰒙")
+	if m.failed.ssh > 0 {
+		text += fmt.Sprintf(" ssh %s", m.ctx.locale.int(int64(m.failed.ssh)))
+	}
+	if m.failed.sudo > 0 {
+		text += fmt.Sprintf(" sudo %s", m.ctx.locale.int(int64(m.failed.sudo)))
+	}
+	return text
+}
+
+func (m *SecurityModule) Style() lipgloss.Style {
+	return criticalStyle
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type sessionsMsg []loginSession
+
+// sessions come and go at login and logout; checking every few seconds is
+// plenty
+const sessionInterval = 10
+
+// SessionModule shows the seat the bar's session is on and how many other
+// graphical sessions are open, and stays empty when there's only this one.
+// Left click lists the sessions.
+type SessionModule struct {
+	ctx      *moduleContext
+	sessions []loginSession
+	ticks    int
+	ready    bool
+}
+
+func init() {
+	RegisterModule("session", func(ctx *moduleContext) Module {
+		return &SessionModule{ctx: ctx}
+	})
+}
+
+func (m *SessionModule) Name() string {
+	return "session"
+}
+
+func (m *SessionModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *SessionModule) fetch() tea.Cmd {
+	return moduleCmd(m.Name(), func() tea.Msg {
+		sessions, err := fetchSessions()
+		if err != nil {
+			log.Printf("session: %v", err)
+		}
+		return sessionsMsg(sessions)
+	})
+}
+
+func (m *SessionModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if m.ticks%sessionInterval == 0 {
+			return m.fetch()
+		}
+	case sessionsMsg:
+		m.sessions = msg
+		m.ready = true
+	case popupMsg:
+		if msg.open {
+			return m.fetch()
+		}
+	}
+	return nil
+}
+
+func (m *SessionModule) Ready() bool {
+	return m.ready
+}
+
+// graphical lists the graphical sessions, the bar's own included even if
+// it's somehow not one.
+func (m *SessionModule) graphical() []loginSession {
+	var list []loginSession
+	for _, s := range m.sessions {
+		if s.graphical() || s.current {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+func (m *SessionModule) current() (loginSession, bool) {
+	for _, s := range m.sessions {
+		if s.current {
+			return s, true
+		}
+	}
+	return loginSession{}, false
+}
+
+func (m *SessionModule) Vars(vars exprVars) {
+	current, _ := m.current()
+	vars["session.id"] = current.id
+	vars["session.seat"] = current.seat
+	vars["session.count"] = len(m.graphical())
+}
+
+func (m *SessionModule) Describe() string {
+	others := len(m.graphical()) - 1
+	if !m.ready || others <= 0 {
+		return ""
+	}
+	current, ok := m.current()
+	if !ok {
+		return fmt.Sprintf("%d graphical sessions", others+1)
+	}
+	return fmt.Sprintf("session %s on %s, %d other sessions", current.id, current.seat, others)
+}
+
+func (m *SessionModule) Render() string {
+	if !m.ready {
+		return placeholder("󰍹")
+	}
+	sessions := m.graphical()
+	if len(sessions) < 2 {
+		return ""
+	}
+	current, ok := m.current()
+	if !ok {
+		return fmt.Sprintf("%s %d", icon("󰍹"), len(sessions))
+	}
+	return fmt.Sprintf("%s %s · %d", icon("󰍹"), current.seat, len(sessions))
+}
+
+func (m *SessionModule) Style() lipgloss.Style {
+	return boxStyle
+}
+
+func (m *SessionModule) Popup() []segments {
+	var rows []segments
+	for _, s := range m.graphical() {
+		mark := " "
+		switch {
+		case s.current:
+			mark = "●"
+		case s.active:
+			mark = "○"
+		}
+		where := s.seat
+		if s.vt > 0 {
+			where += fmt.Sprintf(" vt%d", s.vt)
+		}
+		var row segments
+		row.add("", fmt.Sprintf(" %s %s %s %s %s ", mark, padRight(s.id, 4), padRight(truncate(s.user, 12), 12), padRight(where, 10), s.kind))
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		var row segments
+		row.add("", " "+m.ctx.locale.text("no sessions")+" ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (m *SessionModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		return togglePopup(m.Name())
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type taskbarMsg struct {
+	windows    []HyprlandWindow
+	active     string
+	workspace  int
+	workspaces []int
+}
+
+// TaskbarModule lists the windows on the active workspace. Left click
+// focuses a window; right click opens a popup of actions for it: close,
+// float toggle, pin and move to another workspace.
+type TaskbarModule struct {
+	ctx        *moduleContext
+	windows    []HyprlandWindow
+	active     string
+	workspace  int
+	workspaces []int
+	selected   string
+	open       bool
+	ticks      int
+}
+
+// events that change which windows exist or where they are, or which
+// workspaces there are to move them to
+var taskbarEvents = map[string]bool{
+	"openwindow":         true,
+	"closewindow":        true,
+	"movewindow":         true,
+	"activewindowv2":     true,
+	"windowtitle":        true,
+	"changefloatingmode": true,
+	"pin":                true,
+	"workspace":          true,
+	"focusedmon":         true,
+	"createworkspace":    true,
+	"destroyworkspace":   true,
+}
+
+func init() {
+	RegisterModule("taskbar", func(ctx *moduleContext) Module {
+		return newTaskbarModule(ctx)
+	})
+}
+
+func newTaskbarModule(ctx *moduleContext) *TaskbarModule {
+	return &TaskbarModule{ctx: ctx}
+}
+
+func (m *TaskbarModule) Name() string {
+	return "taskbar"
+}
+
+func (m *TaskbarModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *TaskbarModule) fetch() tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		windows, err := hc.GetWindows()
+		if err != nil {
+			return nil
+		}
+		msg := taskbarMsg{windows: windows}
+		if win, err := hc.GetActiveWindow(); err == nil {
+			msg.active = win.Address
+		}
+		if ws, err := hc.GetActiveWorkspace(); err == nil {
+			msg.workspace = ws.ID
+		}
+		if workspaces, err := hc.GetWorkspaces(); err == nil {
+			for _, ws := range workspaces {
+				if ws.ID > 0 {
+					msg.workspaces = append(msg.workspaces, ws.ID)
+				}
+			}
+			slices.Sort(msg.workspaces)
+		}
+		return msg
+	})
+}
+
+func (m *TaskbarModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if hyprPoll(m.ctx.hypr, m.ticks) {
+			return m.fetch()
+		}
+	case hyprEventMsg:
+		if taskbarEvents[msg.Type] {
+			return m.fetch()
+		}
+	case taskbarMsg:
+		m.windows = msg.windows
+		m.active = msg.active
+		m.workspace = msg.workspace
+		m.workspaces = msg.workspaces
+	case popupMsg:
+		m.open = msg.open
+		if !msg.open {
+			m.selected = ""
+		}
+	}
+	return nil
+}
+
+func (m *TaskbarModule) visible() []HyprlandWindow {
+	var windows []HyprlandWindow
+	for _, win := range m.windows {
+		if win.Workspace.ID == m.workspace {
+			windows = append(windows, win)
+		}
+	}
+	return windows
+}
+
+func (m *TaskbarModule) window(address string) (HyprlandWindow, bool) {
+	for _, win := range m.windows {
+		if win.Address == address {
+			return win, true
+		}
+	}
+	return HyprlandWindow{}, false
+}
+
+func (m *TaskbarModule) Vars(vars exprVars) {
+	vars["taskbar.windows"] = len(m.visible())
+}
+
+func (m *TaskbarModule) Render() string {
+	return m.Segments().render()
+}
+
+func (m *TaskbarModule) Segments() segments {
+	var s segments
+	for _, win := range m.visible() {
+		style := windowTitleStyle
+		if win.Address == m.active {
+			style = activeBoxStyle
+		}
+		s.add(win.Address, style.Render(truncate(win.Class, 14)))
+	}
+	return s
+}
+
+func (m *TaskbarModule) Style() lipgloss.Style {
+	return windowTitleStyle
+}
+
+func (m *TaskbarModule) Popup() []segments {
+	win, ok := m.window(m.selected)
+	if !ok {
+		return nil
+	}
+
+	var title segments
+	title.add("", " "+truncate(win.Title, 40)+" ")
+
+	var actions segments
+	actions.add("action:close", " "+icon("󰅖")+" close ")
+	if win.Floating {
+		actions.add("action:float", " "+icon("󰕰")+" tile ")
+	} else {
+		actions.add("action:float", " "+icon("󰉈")+" float ")
+	}
+	if win.Pinned {
+		actions.add("action:pin", " "+icon("󰐄")+" unpin ")
+	} else {
+		actions.add("action:pin", " "+icon("󰐃")+" pin ")
+	}
+
+	var move segments
+	move.add("", " move to")
+	next := 1
+	for _, id := range m.workspaces {
+		if id != win.Workspace.ID {
+			move.add(fmt.Sprintf("move:%d", id), fmt.Sprintf(" %d", id))
+		}
+		next = max(next, id+1)
+	}
+	move.add(fmt.Sprintf("move:%d", next), " +")
+	move.pad(1)
+
+	return []segments{title, actions, move}
+}
+
+func (m *TaskbarModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+
+	if action, ok := strings.CutPrefix(target, "action:"); ok {
+		if msg.Type != tea.MouseLeft {
+			return nil
+		}
+		address := m.selected
+		switch action {
+		case "close":
+			return m.dispatch(func() error { return hc.CloseWindow(address) }, true)
+		case "float":
+			return m.dispatch(func() error { return hc.ToggleFloatingWindow(address) }, false)
+		case "pin":
+			return m.dispatch(func() error { return hc.TogglePin(address) }, false)
+		}
+		return nil
+	}
+	if ws, ok := strings.CutPrefix(target, "move:"); ok {
+		id, err := strconv.Atoi(ws)
+		if err != nil || msg.Type != tea.MouseLeft {
+			return nil
+		}
+		address := m.selected
+		return m.dispatch(func() error { return hc.MoveWindowToWorkspace(address, id) }, true)
+	}
+
+	if _, ok := m.window(target); !ok {
+		return nil
+	}
+	switch msg.Type {
+	case tea.MouseLeft:
+		return m.dispatch(func() error { return hc.FocusWindow(target) }, false)
+	case tea.MouseRight:
+		if m.open && m.selected != target {
+			m.selected = target
+			return nil
+		}
+		m.selected = target
+		return togglePopup(m.Name())
+	}
+	return nil
+}
+
+// dispatch runs a compositor action, closing the popup when the action
+// takes the window away.
+func (m *TaskbarModule) dispatch(action func() error, closes bool) tea.Cmd {
+	run := hyprActionCmd(m.ctx.hypr, action, m.fetch())
+	if closes {
+		return tea.Batch(run, closePopup())
+	}
+	return run
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type sharedMsg []sharedConnection
+
+// TetherModule appears while this machine shares its connection as a
+// hotspot or tether, with the number of connected clients. Clicking it
+// stops sharing.
+type TetherModule struct {
+	ctx    *moduleContext
+	shared []sharedConnection
+	ticks  int
+}
+
+func init() {
+	RegisterModule("tether", func(ctx *moduleContext) Module {
+		return newTetherModule(ctx)
+	})
+}
+
+func newTetherModule(ctx *moduleContext) *TetherModule {
+	return &TetherModule{ctx: ctx}
+}
+
+func (m *TetherModule) Name() string {
+	return "tether"
+}
+
+func (m *TetherModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *TetherModule) fetch() tea.Cmd {
+	return moduleCmd(m.Name(), func() tea.Msg {
+		shared, err := fetchSharedConnections()
+		if err != nil {
+			return nil
+		}
+		return sharedMsg(shared)
+	})
+}
+
+func (m *TetherModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if m.ticks%5 == 0 {
+			return m.fetch()
+		}
+	case sharedMsg:
+		m.shared = msg
+	}
+	return nil
+}
+
+func (m *TetherModule) clients() int {
+	n := 0
+	for _, s := range m.shared {
+		n += s.clients
+	}
+	return n
+}
+
+func (m *TetherModule) Vars(vars exprVars) {
+	vars["tether.active"] = len(m.shared) > 0
+	vars["tether.clients"] = m.clients()
+}
+
+func (m *TetherModule) Render() string {
+	if len(m.shared) == 0 {
+		return ""
+	}
+	glyph := "󰌘"
+	for _, s := range m.shared {
+		if s.hotspot {
+			glyph = "󰀂"
+		}
+	}
+	return fmt.Sprintf("%s %d", icon(glyph), m.clients())
+}
+
+func (m *TetherModule) Style() lipgloss.Style {
+	return activeBoxStyle
+}
+
+func (m *TetherModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type != tea.MouseLeft || len(m.shared) == 0 {
+		return nil
+	}
+	shared := m.shared
+	return moduleCmd(m.Name(), func() tea.Msg {
+		for _, s := range shared {
+			if err := nmDeactivate(s.active); err != nil {
+				log.Printf("tether: failed to stop %s: %v", s.name, err)
+			}
+		}
+		shared, _ := fetchSharedConnections()
+		return sharedMsg(shared)
+	})
+}
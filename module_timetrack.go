@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type activeClassMsg string
+
+// TimeTrackModule attributes focused time to the active window's class,
+// following activewindow events, and keeps a file per day. Left click
+// opens today's summary.
+type TimeTrackModule struct {
+	ctx      *moduleContext
+	activity activity
+	class    string
+	tally    timeTally
+	ticks    int
+}
+
+func init() {
+	RegisterModule("timetrack", func(ctx *moduleContext) Module {
+		return newTimeTrackModule(ctx)
+	})
+}
+
+func newTimeTrackModule(ctx *moduleContext) *TimeTrackModule {
+	return &TimeTrackModule{ctx: ctx, activity: newActivity(), tally: newTimeTally("classes")}
+}
+
+func (m *TimeTrackModule) Name() string {
+	return "timetrack"
+}
+
+func (m *TimeTrackModule) Init() tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		win, err := hc.GetActiveWindow()
+		if err != nil {
+			return nil
+		}
+		return activeClassMsg(win.Class)
+	})
+}
+
+func (m *TimeTrackModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		class := m.class
+		if idle := m.ctx.config.TimeTrack.IdleSeconds; idle > 0 && m.activity.idle() >= time.Duration(idle)*time.Second {
+			class = ""
+		}
+		cmds := []tea.Cmd{m.tally.tick(time.Time(msg), class), fetchCursor(m.Name(), m.ctx.hypr)}
+		if m.ticks%60 == 0 {
+			cmds = append(cmds, m.tally.save())
+		}
+		return tea.Batch(cmds...)
+	case activeClassMsg:
+		m.class = string(msg)
+	case hyprEventMsg:
+		m.activity.observe(msg)
+		if msg.Type == "activewindow" && len(msg.Data) > 0 {
+			m.class = msg.Data[0]
+		}
+	default:
+		m.activity.observe(msg)
+	}
+	return nil
+}
+
+func (m *TimeTrackModule) Vars(vars exprVars) {
+	vars["timetrack.total"] = int(m.tally.total().Seconds())
+	if ranked := m.tally.ranked(); len(ranked) > 0 {
+		vars["timetrack.top"] = ranked[0]
+	}
+}
+
+func (m *TimeTrackModule) Export() any {
+	return m.tally.export()
+}
+
+func (m *TimeTrackModule) Render() string {
+	if m.ctx.config.TimeTrack.Show == "total" {
+		return icon("󱎫") + " " + shortDuration(m.tally.total())
+	}
+	ranked := m.tally.ranked()
+	if len(ranked) == 0 {
+		return icon("󱎫")
+	}
+	top := ranked[0]
+	return fmt.Sprintf("%s %s %s", icon("󱎫"), truncate(top, 20), shortDuration(m.tally.get(top)))
+}
+
+func (m *TimeTrackModule) Style() lipgloss.Style {
+	return boxStyle
+}
+
+func (m *TimeTrackModule) Popup() []segments {
+	return m.tally.popup(20)
+}
+
+func (m *TimeTrackModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		return togglePopup(m.Name())
+	}
+	return nil
+}
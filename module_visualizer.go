@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type VisualizerConfig struct {
+	Bars      int `json:"bars"`
+	Framerate int `json:"framerate"`
+}
+
+type visualizerFrame []int
+
+// cava levels are requested in the range 0..len(visualizerBlocks)-1
+var visualizerBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+const cavaConfig = `[general]
+bars = %d
+framerate = %d
+
+[output]
+method = raw
+raw_target = /dev/stdout
+data_format = ascii
+ascii_max_range = %d
+bar_delimiter = 59
+frame_delimiter = 10
+`
+
+// VisualizerModule renders a small spectrum from cava's raw output. Frames
+// arrive on their own cadence, independent of the one second tick.
+type VisualizerModule struct {
+	ctx    *moduleContext
+	frames chan visualizerFrame
+	levels []int
+}
+
+func init() {
+	RegisterModule("visualizer", func(ctx *moduleContext) Module {
+		return newVisualizerModule(ctx)
+	})
+}
+
+func newVisualizerModule(ctx *moduleContext) *VisualizerModule {
+	return &VisualizerModule{ctx: ctx}
+}
+
+func (m *VisualizerModule) Name() string {
+	return "visualizer"
+}
+
+func (m *VisualizerModule) config() VisualizerConfig {
+	c := m.ctx.config.Visualizer
+	if c.Bars <= 0 {
+		c.Bars = 12
+	}
+	if c.Framerate <= 0 {
+		c.Framerate = 20
+	}
+	return c
+}
+
+func (m *VisualizerModule) Init() tea.Cmd {
+	c := m.config()
+	m.levels = make([]int, c.Bars)
+
+	path := fmt.Sprintf("%s/tui-bar-cava-%d.conf", os.TempDir(), os.Getpid())
+	conf := fmt.Sprintf(cavaConfig, c.Bars, c.Framerate, len(visualizerBlocks)-1)
+	if err := os.WriteFile(path, []byte(conf), 0o644); err != nil {
+		log.Printf("visualizer: %v", err)
+		return nil
+	}
+
+	cmd := exec.CommandContext(m.ctx.lifetime, "cava", "-p", path)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGTERM}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		log.Printf("visualizer: %v", err)
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		log.Printf("visualizer: cava not available: %v", err)
+		return nil
+	}
+
+	m.frames = make(chan visualizerFrame, 1)
+	go readCavaFrames(cmd, stdout, path, m.frames)
+	return m.wait()
+}
+
+func readCavaFrames(cmd *exec.Cmd, stdout io.Reader, path string, frames chan visualizerFrame) {
+	defer os.Remove(path)
+	defer close(frames)
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSuffix(scanner.Text(), ";"), ";")
+		frame := make(visualizerFrame, len(fields))
+		for i, f := range fields {
+			frame[i], _ = strconv.Atoi(f)
+		}
+		// drop frames the UI hasn't caught up with rather than queueing
+		select {
+		case frames <- frame:
+		default:
+		}
+	}
+	cmd.Wait()
+}
+
+func (m *VisualizerModule) wait() tea.Cmd {
+	frames := m.frames
+	return moduleCmd(m.Name(), func() tea.Msg {
+		frame, ok := <-frames
+		if !ok {
+			return nil
+		}
+		return frame
+	})
+}
+
+func (m *VisualizerModule) Update(msg tea.Msg) tea.Cmd {
+	if frame, ok := msg.(visualizerFrame); ok {
+		m.levels = frame
+		return m.wait()
+	}
+	return nil
+}
+
+func (m *VisualizerModule) Render() string {
+	out := make([]rune, len(m.levels))
+	for i, level := range m.levels {
+		out[i] = visualizerBlocks[max(0, min(len(visualizerBlocks)-1, level))]
+	}
+	return string(out)
+}
+
+func (m *VisualizerModule) Style() lipgloss.Style {
+	return boxStyle
+}
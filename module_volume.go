@@ -0,0 +1,179 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type volumeMsg audioState
+
+const volumeStep = 5
+
+// headset battery at or below this turns the module to the warning style
+const headsetLowBattery = 20
+
+// VolumeModule shows the default sink's volume. Scroll adjusts it, right
+// click mutes and left click opens a per-application mixer popup.
+type VolumeModule struct {
+	ctx   *moduleContext
+	state audioState
+	open  bool
+	ready bool
+}
+
+func init() {
+	RegisterModule("volume", func(ctx *moduleContext) Module {
+		return newVolumeModule(ctx)
+	})
+}
+
+func newVolumeModule(ctx *moduleContext) *VolumeModule {
+	return &VolumeModule{ctx: ctx}
+}
+
+func (m *VolumeModule) Name() string {
+	return "volume"
+}
+
+func (m *VolumeModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *VolumeModule) fetch() tea.Cmd {
+	withStreams := m.open
+	return moduleCmd(m.Name(), func() tea.Msg {
+		state, err := fetchAudioState(withStreams)
+		if err != nil {
+			log.Printf("volume: %v", err)
+		}
+		return volumeMsg(state)
+	})
+}
+
+func (m *VolumeModule) action(do func() error) tea.Cmd {
+	withStreams := m.open
+	return moduleCmd(m.Name(), func() tea.Msg {
+		if err := do(); err != nil {
+			log.Printf("volume: %v", err)
+		}
+		state, _ := fetchAudioState(withStreams)
+		return volumeMsg(state)
+	})
+}
+
+func (m *VolumeModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		return m.fetch()
+	case volumeMsg:
+		m.state = audioState(msg)
+		m.ready = true
+	case popupMsg:
+		m.open = msg.open
+		if m.open {
+			return m.fetch()
+		}
+	}
+	return nil
+}
+
+func (m *VolumeModule) Ready() bool {
+	return m.ready
+}
+
+func (m *VolumeModule) Vars(vars exprVars) {
+	vars["volume.level"] = m.state.volume
+	vars["volume.muted"] = m.state.muted
+	vars["volume.streams"] = len(m.state.streams)
+	if m.state.headset != nil {
+		vars["volume.headset"] = m.state.headset.percent
+	}
+}
+
+func (m *VolumeModule) Describe() string {
+	text := fmt.Sprintf("volume %d percent", m.state.volume)
+	switch {
+	case !m.ready:
+		return ""
+	case m.state.muted:
+		text = "volume muted"
+	}
+	if h := m.state.headset; h != nil {
+		text += fmt.Sprintf(", %s battery %d percent", cmp.Or(h.name, "headset"), h.percent)
+	}
+	return text
+}
+
+func (m *VolumeModule) Render() string {
+	if !m.ready {
+		return placeholder("󰕾")
+	}
+	text := fmt.Sprintf("%s %d%%", volumeIcon(m.state.volume, false), m.state.volume)
+	if m.state.muted {
+		text = volumeIcon(0, true) + " " + m.ctx.locale.text("muted")
+	}
+	if h := m.state.headset; h != nil {
+		text += fmt.Sprintf(" %s %d%%", icon("󰋋"), h.percent)
+	}
+	return text
+}
+
+func (m *VolumeModule) Style() lipgloss.Style {
+	if m.state.muted || m.state.headset != nil && m.state.headset.percent <= headsetLowBattery {
+		return warningStyle
+	}
+	return boxStyle
+}
+
+func (m *VolumeModule) Popup() []segments {
+	if len(m.state.streams) == 0 {
+		var s segments
+		s.add("", " "+m.ctx.locale.text("no active streams")+" ")
+		return []segments{s}
+	}
+
+	rows := make([]segments, 0, len(m.state.streams))
+	for _, stream := range m.state.streams {
+		var s segments
+		s.add(fmt.Sprintf("stream:%d", stream.id), fmt.Sprintf(" %s %s %s %3d%% ",
+			volumeIcon(stream.volume, stream.muted), padRight(truncate(stream.app, 16), 16), volumeBar(stream.volume, 10), stream.volume))
+		rows = append(rows, s)
+	}
+	return rows
+}
+
+func (m *VolumeModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if id, ok := strings.CutPrefix(target, "stream:"); ok {
+		stream, err := strconv.Atoi(id)
+		if err != nil {
+			return nil
+		}
+		switch msg.Type {
+		case tea.MouseLeft:
+			return m.action(func() error { return toggleStreamMute(stream) })
+		case tea.MouseWheelUp:
+			return m.action(func() error { return adjustStreamVolume(stream, volumeStep) })
+		case tea.MouseWheelDown:
+			return m.action(func() error { return adjustStreamVolume(stream, -volumeStep) })
+		}
+		return nil
+	}
+
+	switch msg.Type {
+	case tea.MouseLeft:
+		return togglePopup(m.Name())
+	case tea.MouseRight:
+		return m.action(toggleSinkMute)
+	case tea.MouseWheelUp:
+		return m.action(func() error { return adjustSinkVolume(volumeStep) })
+	case tea.MouseWheelDown:
+		return m.action(func() error { return adjustSinkVolume(-volumeStep) })
+	}
+	return nil
+}
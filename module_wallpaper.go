@@ -0,0 +1,132 @@
+package main
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type wallpaperMsg struct {
+	backend string
+	path    string
+}
+
+type WallpaperModule struct {
+	ctx     *moduleContext
+	backend string
+	path    string
+	ticks   int
+	ready   bool
+}
+
+func init() {
+	RegisterModule("wallpaper", func(ctx *moduleContext) Module {
+		return newWallpaperModule(ctx)
+	})
+}
+
+func newWallpaperModule(ctx *moduleContext) *WallpaperModule {
+	return &WallpaperModule{ctx: ctx}
+}
+
+func (m *WallpaperModule) Name() string {
+	return "wallpaper"
+}
+
+func (m *WallpaperModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *WallpaperModule) fetch() tea.Cmd {
+	configured := m.ctx.config.Wallpaper.Backend
+	backend := m.backend
+	return moduleCmd(m.Name(), func() tea.Msg {
+		if backend == "" {
+			backend = wallpaperBackend(configured)
+		}
+		path, err := currentWallpaper(backend)
+		if err != nil {
+			log.Printf("wallpaper: %v", err)
+		}
+		return wallpaperMsg{backend: backend, path: path}
+	})
+}
+
+func (m *WallpaperModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		// wallpapers rarely change behind our back, poll slowly
+		m.ticks++
+		if m.ticks%30 == 0 {
+			return m.fetch()
+		}
+	case wallpaperMsg:
+		m.backend = msg.backend
+		m.path = msg.path
+		m.ready = true
+	}
+	return nil
+}
+
+func (m *WallpaperModule) Ready() bool {
+	return m.ready
+}
+
+func (m *WallpaperModule) Vars(vars exprVars) {
+	vars["wallpaper.path"] = m.path
+	vars["wallpaper.name"] = m.title()
+}
+
+func (m *WallpaperModule) title() string {
+	name := filepath.Base(m.path)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+func (m *WallpaperModule) Render() string {
+	if !m.ready {
+		return placeholder("󰸉")
+	}
+	if m.path == "" {
+		return icon("󰸉")
+	}
+	return icon("󰸉") + " " + m.title()
+}
+
+func (m *WallpaperModule) Style() lipgloss.Style {
+	return boxStyle
+}
+
+func (m *WallpaperModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	step := 0
+	switch msg.Type {
+	case tea.MouseLeft, tea.MouseWheelDown:
+		step = 1
+	case tea.MouseRight, tea.MouseWheelUp:
+		step = -1
+	default:
+		return nil
+	}
+
+	config := m.ctx.config.Wallpaper
+	backend, current := m.backend, m.path
+	if backend == "" || config.Directory == "" {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		next := nextWallpaper(listWallpapers(config.Directory), current, step)
+		if next == "" {
+			return nil
+		}
+		if err := setWallpaper(backend, next); err != nil {
+			log.Printf("wallpaper: %v", err)
+			return nil
+		}
+		if err := runThemeCommand(config.ThemeCommand, next); err != nil {
+			log.Printf("wallpaper: theme command failed: %v", err)
+		}
+		return wallpaperMsg{backend: backend, path: next}
+	})
+}
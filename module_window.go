@@ -0,0 +1,127 @@
+package main
+
+import (
+	"cmp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type windowTitleMsg struct {
+	title     string
+	container windowContainer
+}
+
+var containerIcons = map[string]string{
+	"flatpak":   "󰏖",
+	"snap":      "󰏗",
+	"toolbox":   "󰆧",
+	"distrobox": "󰆧",
+	"podman":    "󰆧",
+	"docker":    "󰡨",
+}
+
+// titles are cut to this many cells so a long one can't push the right
+// section off screen
+const windowTitleMax = 60
+
+type WindowModule struct {
+	ctx       *moduleContext
+	title     string
+	container windowContainer
+	ticks     int
+}
+
+func init() {
+	RegisterModule("window", func(ctx *moduleContext) Module {
+		return newWindowModule(ctx)
+	})
+}
+
+func newWindowModule(ctx *moduleContext) *WindowModule {
+	return &WindowModule{ctx: ctx}
+}
+
+func (m *WindowModule) Name() string {
+	return "window"
+}
+
+func (m *WindowModule) Init() tea.Cmd {
+	return m.fetch()
+}
+
+func (m *WindowModule) fetch() tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		win, err := hc.GetActiveWindow()
+		if err != nil {
+			return windowTitleMsg{}
+		}
+		return windowTitleMsg{title: cmp.Or(win.Title, win.Class), container: detectContainer(win.Pid)}
+	})
+}
+
+func (m *WindowModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if hyprPoll(m.ctx.hypr, m.ticks) {
+			return m.fetch()
+		}
+	case windowTitleMsg:
+		m.title = msg.title
+		m.container = msg.container
+	case hyprEventMsg:
+		switch msg.Type {
+		case "activewindow":
+			// class,title; an empty pair means nothing is focused. The title
+			// shows right away, the container needs the window's pid.
+			if len(msg.Data) == 2 {
+				m.title = cmp.Or(msg.Data[1], msg.Data[0])
+			}
+			return m.fetch()
+		case "windowtitle":
+			// could be any window, so ask which one has focus
+			return m.fetch()
+		}
+	}
+	return nil
+}
+
+func (m *WindowModule) Vars(vars exprVars) {
+	vars["window.title"] = m.title
+	vars["window.container"] = m.container.kind
+}
+
+func (m *WindowModule) Describe() string {
+	if m.container.kind != "" {
+		return m.title + ", in " + cmp.Or(m.container.name, m.container.kind)
+	}
+	return m.title
+}
+
+func (m *WindowModule) Render() string {
+	title := truncate(m.title, windowTitleMax)
+	if m.container.kind == "" || title == "" {
+		return title
+	}
+	return containerBadge(m.container) + " " + title
+}
+
+// containerBadge names the container, or its kind when it has no name;
+// a flatpak's app id is shortened to its last part.
+func containerBadge(c windowContainer) string {
+	name := c.name
+	if c.kind == "flatpak" {
+		name = name[strings.LastIndex(name, ".")+1:]
+	}
+	return containerStyle.Render(icon(containerIcons[c.kind]) + " " + truncate(cmp.Or(name, c.kind), 16))
+}
+
+func (m *WindowModule) Style() lipgloss.Style {
+	return windowTitleStyle
+}
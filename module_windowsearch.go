@@ -0,0 +1,203 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const windowSearchRows = 8
+
+type windowListMsg []HyprlandWindow
+
+// WindowSearchModule is a window switcher: clicking it (or `tui-bar msg
+// popup windows`) opens a popup with a search field over every window,
+// fuzzy matched on class and title. Enter focuses the selected window.
+type WindowSearchModule struct {
+	ctx      *moduleContext
+	input    textinput.Model
+	windows  []HyprlandWindow
+	matches  []HyprlandWindow
+	selected int
+}
+
+func init() {
+	RegisterModule("windows", func(ctx *moduleContext) Module {
+		return newWindowSearchModule(ctx)
+	})
+}
+
+func newWindowSearchModule(ctx *moduleContext) *WindowSearchModule {
+	input := textinput.New()
+	input.Prompt = icon("󰍉") + " "
+	input.Placeholder = "search windows"
+	input.Width = 40
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return &WindowSearchModule{ctx: ctx, input: input}
+}
+
+func (m *WindowSearchModule) Name() string {
+	return "windows"
+}
+
+func (m *WindowSearchModule) Init() tea.Cmd {
+	return nil
+}
+
+func (m *WindowSearchModule) fetch() tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		windows, err := hc.GetWindows()
+		if err != nil {
+			return nil
+		}
+		return windowListMsg(windows)
+	})
+}
+
+func (m *WindowSearchModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case popupMsg:
+		if !msg.open {
+			m.input.Blur()
+			return nil
+		}
+		m.input.Reset()
+		m.input.Focus()
+		return m.fetch()
+	case windowListMsg:
+		m.windows = msg
+		m.filter()
+	}
+	return nil
+}
+
+func (m *WindowSearchModule) filter() {
+	query := strings.ToLower(m.input.Value())
+	type scored struct {
+		win   HyprlandWindow
+		score int
+	}
+	var results []scored
+	for _, win := range m.windows {
+		if score, ok := fuzzyScore(query, strings.ToLower(win.Class+" "+win.Title)); ok {
+			results = append(results, scored{win, score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	m.matches = m.matches[:0]
+	for _, r := range results {
+		m.matches = append(m.matches, r.win)
+	}
+	m.selected = min(m.selected, max(len(m.matches)-1, 0))
+}
+
+// fuzzyScore matches query as a subsequence of text, rewarding runs of
+// consecutive characters and matches at word starts.
+func fuzzyScore(query, text string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(query)
+	score, run, qi := 0, 0, 0
+	prev := ' '
+	for _, r := range text {
+		if qi < len(q) && r == q[qi] {
+			run++
+			score += run
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 3
+			}
+			qi++
+		} else {
+			run = 0
+		}
+		prev = r
+	}
+	return score, qi == len(q)
+}
+
+// HandleKey takes keyboard input while the popup is open.
+func (m *WindowSearchModule) HandleKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "ctrl+c":
+		return nil, false
+	case "esc":
+		return closePopup(), true
+	case "up", "ctrl+p", "shift+tab":
+		m.selected = max(m.selected-1, 0)
+		return nil, true
+	case "down", "ctrl+n", "tab":
+		m.selected = min(m.selected+1, max(len(m.matches)-1, 0))
+		return nil, true
+	case "enter":
+		if m.selected < len(m.matches) {
+			return m.focus(m.matches[m.selected].Address), true
+		}
+		return nil, true
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.selected = 0
+	m.filter()
+	return cmd, true
+}
+
+func (m *WindowSearchModule) focus(address string) tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return closePopup()
+	}
+	return tea.Batch(closePopup(), hyprActionCmd(hc, func() error {
+		return hc.FocusWindow(address)
+	}, nil))
+}
+
+func (m *WindowSearchModule) Render() string {
+	return icon("󰍉")
+}
+
+func (m *WindowSearchModule) Style() lipgloss.Style {
+	return boxStyle
+}
+
+func (m *WindowSearchModule) Popup() []segments {
+	var input segments
+	input.add("", " "+m.input.View()+" ")
+	rows := []segments{input}
+
+	// keep the selection in view
+	start := max(m.selected-windowSearchRows+1, 0)
+	end := min(start+windowSearchRows, len(m.matches))
+	for i := start; i < end; i++ {
+		win := m.matches[i]
+		text := " " + truncate(win.Class, 14) + "  " + truncate(win.Title, 40) + " "
+		if i == m.selected {
+			text = popupSelectedStyle.Render(text)
+		}
+		var row segments
+		row.add("window:"+win.Address, text)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (m *WindowSearchModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type != tea.MouseLeft {
+		return nil
+	}
+	if address, ok := strings.CutPrefix(target, "window:"); ok {
+		return m.focus(address)
+	}
+	return togglePopup(m.Name())
+}
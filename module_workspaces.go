@@ -0,0 +1,496 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type workspacesMsg struct {
+	active     int
+	workspaces []HyprlandWorkspace
+	specials   map[string]string
+}
+
+type occupancyMsg struct {
+	windows []HyprlandWindow
+}
+
+const workspaceHistorySize = 10
+
+// WorkspacesModule shows workspaces grouped by monitor. Middle click goes
+// back to the previous workspace and right click opens the history of
+// recently visited ones.
+type WorkspacesModule struct {
+	ctx        *moduleContext
+	active     int
+	workspaces []HyprlandWorkspace
+	occupancy  *windowOccupancy
+	history    []int
+	ready      bool
+	ticks      int
+	urgent     map[int]bool
+	// the special workspace open on each monitor
+	specials map[string]string
+}
+
+func init() {
+	RegisterModule("workspaces", func(ctx *moduleContext) Module {
+		return newWorkspacesModule(ctx)
+	})
+}
+
+func newWorkspacesModule(ctx *moduleContext) *WorkspacesModule {
+	return &WorkspacesModule{
+		ctx:       ctx,
+		active:    1,
+		occupancy: newWindowOccupancy(),
+		urgent:    make(map[int]bool),
+		specials:  make(map[string]string),
+	}
+}
+
+func (m *WorkspacesModule) Name() string {
+	return "workspaces"
+}
+
+func (m *WorkspacesModule) Init() tea.Cmd {
+	return tea.Batch(m.fetch(), m.fetchOccupancy())
+}
+
+func (m *WorkspacesModule) fetch() tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		msg := workspacesMsg{active: getActiveWorkspace(hc)}
+		if workspaces, err := hc.GetWorkspaces(); err == nil {
+			msg.workspaces = workspaces
+		}
+		if monitors, err := hc.GetMonitors(); err == nil {
+			msg.specials = make(map[string]string)
+			for _, mon := range monitors {
+				if mon.SpecialWorkspace.Name != "" {
+					msg.specials[mon.Name] = mon.SpecialWorkspace.Name
+				}
+			}
+		}
+		return msg
+	})
+}
+
+func (m *WorkspacesModule) fetchOccupancy() tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		windows, err := hc.GetWindows()
+		if err != nil {
+			return nil
+		}
+		return occupancyMsg{windows: windows}
+	})
+}
+
+func (m *WorkspacesModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		if hyprPoll(m.ctx.hypr, m.ticks) {
+			return m.fetch()
+		}
+	case workspacesMsg:
+		m.active = msg.active
+		m.workspaces = msg.workspaces
+		if msg.specials != nil {
+			m.specials = msg.specials
+		}
+		for id := range m.urgent {
+			if !slices.ContainsFunc(m.workspaces, func(ws HyprlandWorkspace) bool { return ws.ID == id }) {
+				delete(m.urgent, id)
+			}
+		}
+		m.ready = true
+		m.focus(msg.active)
+	case occupancyMsg:
+		m.occupancy.reset(msg.windows)
+	case hyprEventMsg:
+		m.occupancy.apply(HyprlandEvent(msg), m.idByName)
+		switch msg.Type {
+		case "workspace":
+			if id, name, ok := workspaceFromEvent(HyprlandEvent(msg)); ok {
+				if id == 0 {
+					id = m.idByName(name)
+				}
+				m.focus(id)
+			}
+		case "focusedmon":
+			// monitor,workspace name
+			if len(msg.Data) == 2 {
+				m.focus(m.idByName(msg.Data[1]))
+			}
+		case "activespecial":
+			// workspace name (empty when closed),monitor
+			if len(msg.Data) == 2 {
+				if msg.Data[0] == "" {
+					delete(m.specials, msg.Data[1])
+				} else {
+					m.specials[msg.Data[1]] = msg.Data[0]
+				}
+			}
+		case "urgent":
+			if len(msg.Data) == 0 {
+				break
+			}
+			if id, ok := m.occupancy.workspaceOf(msg.Data[0]); ok && id != m.active {
+				m.urgent[id] = true
+			}
+		case "createworkspace", "destroyworkspace", "moveworkspace", "renameworkspace",
+			"monitoradded", "monitoraddedv2", "monitorremoved", "monitorremovedv2":
+			return m.fetch()
+		}
+	}
+	return nil
+}
+
+func (m *WorkspacesModule) focus(id int) {
+	if id != 0 {
+		m.active = id
+	}
+	delete(m.urgent, id)
+	m.visit(id)
+}
+
+// visit records a workspace as the most recent, most recent first.
+func (m *WorkspacesModule) visit(id int) {
+	if id <= 0 || len(m.history) > 0 && m.history[0] == id {
+		return
+	}
+	if i := slices.Index(m.history, id); i >= 0 {
+		m.history = slices.Delete(m.history, i, i+1)
+	}
+	m.history = slices.Insert(m.history, 0, id)
+	if len(m.history) > workspaceHistorySize {
+		m.history = m.history[:workspaceHistorySize]
+	}
+}
+
+func (m *WorkspacesModule) SaveState() any {
+	return m.history
+}
+
+func (m *WorkspacesModule) RestoreState(data json.RawMessage) {
+	json.Unmarshal(data, &m.history)
+}
+
+func (m *WorkspacesModule) Popup() []segments {
+	labels := m.labels()
+	var rows []segments
+	// the current workspace heads the history; it isn't somewhere to go back to
+	for _, id := range m.history[min(1, len(m.history)):] {
+		var row segments
+		text := fmt.Sprintf(" %s %d", icon("󰕍"), id)
+		if labels[id] != "" {
+			text += " " + labels[id]
+		}
+		row.add(fmt.Sprintf("history:%d", id), text+" ")
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		var row segments
+		row.add("", " "+m.ctx.locale.text("no history yet")+" ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (m *WorkspacesModule) idByName(name string) int {
+	for _, ws := range m.workspaces {
+		if ws.Name == name {
+			return ws.ID
+		}
+	}
+	id, _ := strconv.Atoi(name)
+	return id
+}
+
+func (m *WorkspacesModule) Ready() bool {
+	return m.ready || m.ctx.hypr == nil
+}
+
+func (m *WorkspacesModule) Vars(vars exprVars) {
+	vars["workspace"] = m.active
+	vars["workspace.urgent"] = len(m.urgent) > 0
+}
+
+func (m *WorkspacesModule) Describe() string {
+	if !m.ready {
+		return ""
+	}
+	if len(m.urgent) > 0 {
+		return fmt.Sprintf("workspace %d, %d urgent", m.active, len(m.urgent))
+	}
+	return fmt.Sprintf("workspace %d", m.active)
+}
+
+func (m *WorkspacesModule) Render() string {
+	return m.Segments().render()
+}
+
+func (m *WorkspacesModule) Style() lipgloss.Style {
+	return workspaceStyle
+}
+
+func (m *WorkspacesModule) Segments() segments {
+	var s segments
+
+	if !m.Ready() {
+		s.add("", workspaceStyle.Render(placeholder("󰕰")))
+		return s
+	}
+
+	counts := m.occupancy.counts()
+	labels := m.labels()
+
+	var monitors []string
+	byMonitor := make(map[string][]HyprlandWorkspace)
+	focusedMonitor := ""
+	for _, ws := range m.visible() {
+		if _, ok := byMonitor[ws.Monitor]; !ok {
+			monitors = append(monitors, ws.Monitor)
+		}
+		byMonitor[ws.Monitor] = append(byMonitor[ws.Monitor], ws)
+		if ws.ID == m.active {
+			focusedMonitor = ws.Monitor
+		}
+	}
+
+	for _, mon := range monitors {
+		if len(monitors) > 1 {
+			label := monitorLabelStyle.Render(mon)
+			if mon == focusedMonitor {
+				label = monitorLabelActiveStyle.Render(mon)
+			}
+			s.add("monitor:"+mon, label)
+		}
+		for _, ws := range byMonitor[mon] {
+			target := workspaceTarget(ws)
+			windows := 0
+			if m.ctx.config.Workspaces.ShowWindowCount {
+				windows = counts[ws.ID]
+			}
+			s.add(target, renderWorkspaceButton(workspaceLabel(ws), windows, labels[ws.ID], m.workspaceStyle(ws, counts[ws.ID] > 0)))
+		}
+	}
+	return s
+}
+
+// visible lists the workspaces to draw: every existing one plus the
+// persistent ones, numbered workspaces first, then named, then special.
+func (m *WorkspacesModule) visible() []HyprlandWorkspace {
+	var list []HyprlandWorkspace
+	monitor := ""
+	for _, ws := range m.workspaces {
+		list = append(list, ws)
+		if ws.ID == m.active {
+			monitor = ws.Monitor
+		}
+	}
+
+	// persistent workspaces that don't exist yet go with the focused monitor
+	for _, name := range m.ctx.config.Workspaces.Persistent {
+		if slices.ContainsFunc(list, func(ws HyprlandWorkspace) bool {
+			return ws.Name == name
+		}) {
+			continue
+		}
+		id, _ := strconv.Atoi(name)
+		list = append(list, HyprlandWorkspace{ID: id, Name: name, Monitor: monitor})
+	}
+	if len(list) == 0 {
+		list = append(list, HyprlandWorkspace{ID: m.active, Name: strconv.Itoa(m.active)})
+	}
+
+	rank := func(ws HyprlandWorkspace) int {
+		switch {
+		case isSpecialWorkspace(ws.Name):
+			return 2
+		case ws.ID <= 0:
+			return 1
+		}
+		return 0
+	}
+	slices.SortStableFunc(list, func(a, b HyprlandWorkspace) int {
+		if c := cmp.Compare(rank(a), rank(b)); c != 0 {
+			return c
+		}
+		if rank(a) == 0 {
+			return cmp.Compare(a.ID, b.ID)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return list
+}
+
+func (m *WorkspacesModule) workspaceStyle(ws HyprlandWorkspace, occupied bool) lipgloss.Style {
+	switch {
+	case isSpecialWorkspace(ws.Name):
+		for _, name := range m.specials {
+			if name == ws.Name {
+				return workspaceActiveStyle
+			}
+		}
+		return workspaceSpecialStyle
+	case ws.ID != 0 && ws.ID == m.active:
+		return workspaceActiveStyle
+	case m.urgent[ws.ID]:
+		return workspaceUrgentStyle
+	case !occupied && ws.Windows == 0:
+		return workspaceEmptyStyle
+	}
+	return workspaceStyle
+}
+
+// workspaceTarget picks how a click reaches the workspace: by number, by
+// name, or toggling it when it's special.
+func workspaceTarget(ws HyprlandWorkspace) string {
+	switch {
+	case isSpecialWorkspace(ws.Name):
+		return "special:" + specialName(ws.Name)
+	case ws.ID > 0:
+		return fmt.Sprintf("workspace:%d", ws.ID)
+	}
+	return "named:" + ws.Name
+}
+
+func isSpecialWorkspace(name string) bool {
+	return name == "special" || strings.HasPrefix(name, "special:")
+}
+
+// specialName strips the "special:" prefix; the default special workspace
+// has no name at all.
+func specialName(name string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(name, "special"), ":")
+}
+
+// workspaceLabel is the number for numbered workspaces, the name for named
+// ones and the name without its prefix for special ones.
+func workspaceLabel(ws HyprlandWorkspace) string {
+	if isSpecialWorkspace(ws.Name) {
+		name := strings.TrimPrefix(strings.TrimPrefix(ws.Name, "special"), ":")
+		return strings.TrimSpace(icon("") + " " + name)
+	}
+	if ws.ID > 0 {
+		return strconv.Itoa(ws.ID)
+	}
+	return ws.Name
+}
+
+// labels names workspaces after their dominant app when auto naming is on.
+func (m *WorkspacesModule) labels() map[int]string {
+	config := m.ctx.config.Workspaces
+	if !config.AutoName {
+		return nil
+	}
+	labels := make(map[int]string)
+	for ws, class := range m.occupancy.dominant() {
+		label := truncate(class, 10)
+		for name, glyph := range config.ClassIcons {
+			if strings.EqualFold(name, class) {
+				label = icon(glyph)
+				break
+			}
+		}
+		labels[ws] = label
+	}
+	return labels
+}
+
+func renderWorkspaceButton(name string, windows int, label string, style lipgloss.Style) string {
+	ws := name
+	if windows > 0 {
+		ws += superscript(windows)
+	}
+	if label != "" {
+		ws += " " + label
+	}
+	return style.Render(ws)
+}
+
+func (m *WorkspacesModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+
+	kind, arg, _ := strings.Cut(target, ":")
+	switch msg.Type {
+	case tea.MouseMiddle:
+		return hyprActionCmd(hc, hc.SwitchWorkspacePrevious, m.fetch())
+
+	case tea.MouseRight:
+		return togglePopup(m.Name())
+
+	case tea.MouseLeft:
+		switch kind {
+		case "history":
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil
+			}
+			return tea.Batch(closePopup(), hyprActionCmd(hc, func() error {
+				return hc.SwitchWorkspace(id)
+			}, m.fetch()))
+		case "monitor":
+			return hyprActionCmd(hc, func() error {
+				return hc.FocusMonitor(arg)
+			}, m.fetch())
+		case "workspace":
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil
+			}
+			if msg.Shift {
+				return hyprActionCmd(hc, func() error {
+					return hc.MoveToWorkspace(id)
+				}, m.fetch())
+			}
+			return hyprActionCmd(hc, func() error {
+				return hc.SwitchWorkspace(id)
+			}, m.fetch())
+		case "named":
+			return hyprActionCmd(hc, func() error {
+				return hc.SwitchWorkspaceByName(arg)
+			}, m.fetch())
+		case "special":
+			return hyprActionCmd(hc, func() error {
+				return hc.ToggleSpecialWorkspace(arg)
+			}, m.fetch())
+		}
+
+	case tea.MouseWheelUp, tea.MouseWheelDown:
+		offset := 1
+		if msg.Type == tea.MouseWheelUp {
+			offset = -1
+		}
+		// plain scrolling cycles workspaces, with a modifier it takes the
+		// focused window along
+		if !msg.Shift && !msg.Ctrl {
+			return hyprActionCmd(hc, func() error {
+				return hc.SwitchWorkspaceRelative(offset)
+			}, m.fetch())
+		}
+		return hyprActionCmd(hc, func() error {
+			return hc.MoveToWorkspaceRelative(offset)
+		}, m.fetch())
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type activeWorkspaceMsg string
+
+// WorkspaceStatsModule tallies time per workspace (by name, so named
+// project workspaces keep their history) and shows the current one's
+// total for today. Left click opens the summary; `tui-bar msg stats
+// workspacestats` prints it as JSON.
+type WorkspaceStatsModule struct {
+	ctx       *moduleContext
+	activity  activity
+	workspace string
+	tally     timeTally
+	ticks     int
+}
+
+func init() {
+	RegisterModule("workspacestats", func(ctx *moduleContext) Module {
+		return newWorkspaceStatsModule(ctx)
+	})
+}
+
+func newWorkspaceStatsModule(ctx *moduleContext) *WorkspaceStatsModule {
+	return &WorkspaceStatsModule{ctx: ctx, activity: newActivity(), tally: newTimeTally("workspaces")}
+}
+
+func (m *WorkspaceStatsModule) Name() string {
+	return "workspacestats"
+}
+
+func (m *WorkspaceStatsModule) Init() tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+	return moduleCmd(m.Name(), func() tea.Msg {
+		ws, err := hc.GetActiveWorkspace()
+		if err != nil {
+			return nil
+		}
+		if ws.Name == "" {
+			return activeWorkspaceMsg(strconv.Itoa(ws.ID))
+		}
+		return activeWorkspaceMsg(ws.Name)
+	})
+}
+
+func (m *WorkspaceStatsModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.ticks++
+		workspace := m.workspace
+		if idle := m.ctx.config.TimeTrack.IdleSeconds; idle > 0 && m.activity.idle() >= time.Duration(idle)*time.Second {
+			workspace = ""
+		}
+		cmds := []tea.Cmd{m.tally.tick(time.Time(msg), workspace), fetchCursor(m.Name(), m.ctx.hypr)}
+		if m.ticks%60 == 0 {
+			cmds = append(cmds, m.tally.save())
+		}
+		return tea.Batch(cmds...)
+	case activeWorkspaceMsg:
+		m.workspace = string(msg)
+	case hyprEventMsg:
+		m.activity.observe(msg)
+		if msg.Type == "workspace" {
+			if _, name, ok := workspaceFromEvent(HyprlandEvent(msg)); ok {
+				m.workspace = name
+			}
+		}
+	default:
+		m.activity.observe(msg)
+	}
+	return nil
+}
+
+func (m *WorkspaceStatsModule) Vars(vars exprVars) {
+	vars["workspacestats.current"] = int(m.tally.get(m.workspace).Seconds())
+	vars["workspacestats.total"] = int(m.tally.total().Seconds())
+}
+
+func (m *WorkspaceStatsModule) Export() any {
+	return m.tally.export()
+}
+
+func (m *WorkspaceStatsModule) Render() string {
+	if m.workspace == "" {
+		return icon("󱎫")
+	}
+	return fmt.Sprintf("%s %s %s", icon("󱎫"), truncate(m.workspace, 12), shortDuration(m.tally.get(m.workspace)))
+}
+
+func (m *WorkspaceStatsModule) Style() lipgloss.Style {
+	return boxStyle
+}
+
+func (m *WorkspaceStatsModule) Popup() []segments {
+	return m.tally.popup(12)
+}
+
+func (m *WorkspaceStatsModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		return togglePopup(m.Name())
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// WorldClockConfig lists IANA zones to show next to each other. Label
+// defaults to the zone's abbreviation (PST, CET).
+type WorldClockConfig struct {
+	Zones []WorldClockZone `json:"zones"`
+}
+
+type WorldClockZone struct {
+	Zone  string `json:"zone"`
+	Label string `json:"label"`
+}
+
+type worldZone struct {
+	label string
+	loc   *time.Location
+}
+
+// WorldClockModule shows the configured zones compactly; left click opens
+// a table converting the next few hours across them.
+type WorldClockModule struct {
+	ctx   *moduleContext
+	zones []worldZone
+	now   time.Time
+}
+
+func init() {
+	RegisterModule("worldclock", func(ctx *moduleContext) Module {
+		return newWorldClockModule(ctx)
+	})
+}
+
+func newWorldClockModule(ctx *moduleContext) *WorldClockModule {
+	m := &WorldClockModule{ctx: ctx, now: time.Now()}
+	for _, z := range ctx.config.WorldClock.Zones {
+		loc, err := time.LoadLocation(z.Zone)
+		if err != nil {
+			log.Printf("worldclock: %v", err)
+			continue
+		}
+		m.zones = append(m.zones, worldZone{label: z.Label, loc: loc})
+	}
+	return m
+}
+
+func (m *WorldClockModule) Name() string {
+	return "worldclock"
+}
+
+func (m *WorldClockModule) Init() tea.Cmd {
+	return nil
+}
+
+func (m *WorldClockModule) Update(msg tea.Msg) tea.Cmd {
+	if t, ok := msg.(tickMsg); ok {
+		m.now = time.Time(t)
+	}
+	return nil
+}
+
+// label is resolved per call since the abbreviation follows DST.
+func (z worldZone) name(t time.Time) string {
+	if z.label != "" {
+		return z.label
+	}
+	return t.In(z.loc).Format("MST")
+}
+
+func (m *WorldClockModule) Describe() string {
+	return m.Render()
+}
+
+func (m *WorldClockModule) Render() string {
+	parts := make([]string, len(m.zones))
+	for i, z := range m.zones {
+		parts[i] = z.name(m.now) + " " + m.now.In(z.loc).Format("15:04")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (m *WorldClockModule) Style() lipgloss.Style {
+	return clockStyle
+}
+
+func (m *WorldClockModule) Popup() []segments {
+	if len(m.zones) == 0 {
+		return nil
+	}
+	var header segments
+	header.add("", fmt.Sprintf(" %-6s", "local"))
+	for _, z := range m.zones {
+		header.add("", " "+padRight(truncate(z.name(m.now), 6), 6))
+	}
+	header.add("", " ")
+	rows := []segments{header}
+
+	hour := m.now.Truncate(time.Hour)
+	for i := 0; i < 8; i++ {
+		t := hour.Add(time.Duration(i) * time.Hour)
+		var row segments
+		row.add("", fmt.Sprintf(" %-6s", t.Format("15:04")))
+		for _, z := range m.zones {
+			local := t.In(z.loc)
+			text := local.Format("15:04")
+			// mark when the zone is on another day
+			if d := dayDiff(t, local); d != 0 {
+				text += fmt.Sprintf("%+d", d)
+			}
+			row.add("", fmt.Sprintf(" %-6s", text))
+		}
+		row.add("", " ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func dayDiff(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return int(time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC).Sub(time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+}
+
+func (m *WorldClockModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	if msg.Type == tea.MouseLeft {
+		return togglePopup(m.Name())
+	}
+	return nil
+}
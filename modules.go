@@ -1,36 +1,242 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Module is a self-contained bar widget. It owns its state, receives
+// broadcast messages (ticks, compositor events, resizes) plus any
+// moduleMsg addressed to its Name(), and renders its own content.
 type Module interface {
 	Name() string
-	Update() error
+	Init() tea.Cmd
+	Update(msg tea.Msg) tea.Cmd
 	Render() string
 	Style() lipgloss.Style
 }
 
-type CPUModule struct {
-	usage float64
+// optional module capabilities
+
+type segmentedModule interface {
+	Segments() segments
+}
+
+type mouseHandler interface {
+	HandleMouse(target string, msg tea.MouseMsg) tea.Cmd
+}
+
+type varProvider interface {
+	Vars(vars exprVars)
+}
+
+type readyReporter interface {
+	Ready() bool
+}
+
+type moduleMsg struct {
+	id  string
+	msg tea.Msg
 }
 
-func (m *CPUModule) Name() string {
-	return "cpu"
+func moduleCmd(id string, fetch func() tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return moduleMsg{id: id, msg: fetch()}
+	}
 }
 
-func (m *CPUModule) Update() error {
-	usage, _, _ := fetchSystemStats()
-	m.usage = usage
-	return nil
+type moduleContext struct {
+	lifetime context.Context
+	hypr     *HyprlandClient
+	metrics  *MetricsCollector
+	network  *NetworkWatcher
+	http     *HTTPClient
+	locale   locale
+	units    units
+	config   *Config
+	faults   *moduleFaults
+	frames   *frameStats
+	vars     func() exprVars
+}
+
+func newModules(ctx *moduleContext) []Module {
+	var modules []Module
+	for _, name := range configuredModules(ctx.config) {
+		if factory, ok := moduleRegistry[name]; ok {
+			modules = append(modules, factory(ctx))
+		}
+	}
+	for _, plugin := range ctx.config.Plugins {
+		modules = append(modules, newPluginModule(ctx, plugin))
+	}
+	for _, script := range ctx.config.Scripts {
+		modules = append(modules, newScriptModule(ctx, script))
+	}
+	for _, custom := range ctx.config.Custom {
+		modules = append(modules, newCustomModule(ctx, custom))
+	}
+	ctx.vars = func() exprVars {
+		return collectVars(modules, ctx.faults)
+	}
+	return modules
+}
+
+// LayoutConfig places modules explicitly, left to right within each
+// section, and replaces "modules" when set. Plugins, scripts and custom
+// modules may be listed as "plugin:<name>", "script:<name>" or
+// "custom:<name>"; those left out go by their own position.
+type LayoutConfig struct {
+	Left   []string `json:"left"`
+	Center []string `json:"center"`
+	Right  []string `json:"right"`
+}
+
+func (l LayoutConfig) sections() [][]string {
+	return [][]string{l.Left, l.Center, l.Right}
+}
+
+func (l LayoutConfig) empty() bool {
+	return len(l.Left)+len(l.Center)+len(l.Right) == 0
+}
+
+// modules checks the layout and returns the built-in modules it names.
+func (l LayoutConfig) modules(config *Config) ([]string, error) {
+	var modules []string
+	seen := map[string]bool{}
+	for _, ids := range l.sections() {
+		for _, id := range ids {
+			if seen[id] {
+				return nil, fmt.Errorf("layout: %q listed twice", id)
+			}
+			seen[id] = true
+
+			kind, name, _ := strings.Cut(id, ":")
+			switch {
+			case kind == "plugin" && !slices.ContainsFunc(config.Plugins, func(p PluginConfig) bool { return p.Name == name }):
+				return nil, fmt.Errorf("layout: no plugin named %q", name)
+			case kind == "script" && !slices.ContainsFunc(config.Scripts, func(s ScriptConfig) bool { return s.Name == name }):
+				return nil, fmt.Errorf("layout: no script named %q", name)
+			case kind == "custom" && !slices.ContainsFunc(config.Custom, func(c CustomConfig) bool { return c.Name == name }):
+				return nil, fmt.Errorf("layout: no custom module named %q", name)
+			case kind != "plugin" && kind != "script" && kind != "custom":
+				modules = append(modules, id)
+			}
+		}
+	}
+	return modules, nil
+}
+
+// knownModule reports whether id names a built-in module or a configured
+// plugin, script or custom module.
+func (c *Config) knownModule(id string) bool {
+	kind, name, _ := strings.Cut(id, ":")
+	switch kind {
+	case "plugin":
+		return slices.ContainsFunc(c.Plugins, func(p PluginConfig) bool { return p.Name == name })
+	case "script":
+		return slices.ContainsFunc(c.Scripts, func(s ScriptConfig) bool { return s.Name == name })
+	case "custom":
+		return slices.ContainsFunc(c.Custom, func(cc CustomConfig) bool { return cc.Name == name })
+	}
+	_, ok := moduleRegistry[id]
+	return ok
+}
+
+var barSections = [][]string{
+	{"workspaces", "layout", "window", "taskbar", "windows"},
+	{"clock"},
+	{"hyprland", "cpu", "memory", "disk", "network", "battery"},
+}
+
+var sectionIndex = map[string]int{"left": 0, "center": 1, "right": 2}
+
+// configuredModules is the config's module list plus the debug overlay,
+// which always exists but stays empty until toggled on.
+func configuredModules(config *Config) []string {
+	if slices.Contains(config.Modules, "debug") {
+		return config.Modules
+	}
+	return append(slices.Clone(config.Modules), "debug")
+}
+
+func defaultPosition(name string) string {
+	for i, ids := range barSections {
+		for _, id := range ids {
+			if id == name {
+				return [...]string{"left", "center", "right"}[i]
+			}
+		}
+	}
+	return "right"
+}
+
+func layoutSections(config *Config) [][]string {
+	sections := make([][]string, len(barSections))
+	placed := map[string]bool{}
+	place := func(id, position string) {
+		if placed[id] {
+			return
+		}
+		idx, ok := sectionIndex[position]
+		if !ok {
+			idx = sectionIndex["right"]
+		}
+		sections[idx] = append(sections[idx], id)
+		placed[id] = true
+	}
+	for i, ids := range config.Layout.sections() {
+		for _, id := range ids {
+			place(id, [...]string{"left", "center", "right"}[i])
+		}
+	}
+	for _, name := range configuredModules(config) {
+		place(name, defaultPosition(name))
+	}
+	for _, plugin := range config.Plugins {
+		place("plugin:"+plugin.Name, plugin.Position)
+	}
+	for _, script := range config.Scripts {
+		place("script:"+script.Name, script.Position)
+	}
+	for _, custom := range config.Custom {
+		place("custom:"+custom.Name, custom.Position)
+	}
+	return sections
+}
+
+func renderModule(mod Module) segments {
+	var s segments
+
+	if seg, ok := mod.(segmentedModule); ok {
+		inner := seg.Segments()
+		for i := range inner.zones {
+			inner.zones[i].target = mod.Name() + "/" + inner.zones[i].target
+		}
+		s.append(inner)
+		return s
+	}
+
+	content := mod.Render()
+	if content == "" {
+		return s
+	}
+	s.add(mod.Name(), moduleStyle(mod.Name(), mod.Style()).Render(content))
+	return s
 }
 
-func (m *CPUModule) Render() string {
-	return fmt.Sprintf("󰻠 %.1f%%", m.usage)
+func splitTarget(target string) (string, string) {
+	id, sub, _ := strings.Cut(target, "/")
+	return id, sub
 }
 
-func (m *CPUModule) Style() lipgloss.Style {
-	return cpuStyle
+func moduleReady(mod Module) bool {
+	if r, ok := mod.(readyReporter); ok {
+		return r.Ready()
+	}
+	return true
 }
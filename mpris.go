@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// media players over MPRIS on the session bus
+
+const (
+	mprisPrefix = "org.mpris.MediaPlayer2."
+	mprisPath   = "/org/mpris/MediaPlayer2"
+	mprisRoot   = "org.mpris.MediaPlayer2"
+	mprisPlayer = "org.mpris.MediaPlayer2.Player"
+)
+
+type mediaPlayer struct {
+	bus      string // org.mpris.MediaPlayer2.<name>
+	identity string
+	status   string // Playing, Paused or Stopped
+	track    playedTrack
+	length   time.Duration
+	position time.Duration
+	canSeek  bool
+}
+
+func (p mediaPlayer) playing() bool {
+	return p.status == "Playing"
+}
+
+// watchMPRIS signals on changed whenever a player's state changes or a
+// player appears or goes away, until lifetime ends. Bursts collapse into
+// one signal.
+func watchMPRIS(lifetime context.Context) (<-chan struct{}, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range [][]dbus.MatchOption{
+		{dbus.WithMatchObjectPath(mprisPath), dbus.WithMatchInterface("org.freedesktop.DBus.Properties"), dbus.WithMatchMember("PropertiesChanged")},
+		{dbus.WithMatchObjectPath(mprisPath), dbus.WithMatchInterface(mprisPlayer), dbus.WithMatchMember("Seeked")},
+		{dbus.WithMatchSender("org.freedesktop.DBus"), dbus.WithMatchMember("NameOwnerChanged"), dbus.WithMatchArg0Namespace(mprisRoot)},
+	} {
+		if err := conn.AddMatchSignal(match...); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	changed := make(chan struct{}, 1)
+	go func() {
+		for range signals {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	go func() {
+		<-lifetime.Done()
+		conn.Close()
+	}()
+	return changed, nil
+}
+
+// fetchPlayers lists the running players in bus name order.
+func fetchPlayers() ([]mediaPlayer, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	if err := conn.BusObject().Call("org.freedesktop.DBus.ListNames", 0).Store(&names); err != nil {
+		return nil, err
+	}
+
+	var players []mediaPlayer
+	for _, name := range names {
+		if !strings.HasPrefix(name, mprisPrefix) {
+			continue
+		}
+		obj := conn.Object(name, mprisPath)
+		var props map[string]dbus.Variant
+		if err := obj.Call("org.freedesktop.DBus.Properties.GetAll", 0, mprisPlayer).Store(&props); err != nil {
+			continue
+		}
+		p := mediaPlayer{bus: name, identity: strings.TrimPrefix(name, mprisPrefix)}
+		if v, err := obj.GetProperty(mprisRoot + ".Identity"); err == nil {
+			if identity, _ := v.Value().(string); identity != "" {
+				p.identity = identity
+			}
+		}
+		p.status, _ = props["PlaybackStatus"].Value().(string)
+		p.canSeek = variantBool(props["CanSeek"])
+		if position, ok := props["Position"].Value().(int64); ok {
+			p.position = time.Duration(position) * time.Microsecond
+		}
+		metadata, _ := props["Metadata"].Value().(map[string]dbus.Variant)
+		p.track = playedTrack{Player: p.identity}
+		p.track.Title, _ = metadata["xesam:title"].Value().(string)
+		p.track.Album, _ = metadata["xesam:album"].Value().(string)
+		if artists, ok := metadata["xesam:artist"].Value().([]string); ok {
+			p.track.Artist = strings.Join(artists, ", ")
+		}
+		// players disagree on the type; it's meant to be an int64
+		switch length := metadata["mpris:length"].Value().(type) {
+		case int64:
+			p.length = time.Duration(length) * time.Microsecond
+		case uint64:
+			p.length = time.Duration(length) * time.Microsecond
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+// activePlayer prefers a player that's playing, then one that's paused.
+func activePlayer(players []mediaPlayer) (mediaPlayer, bool) {
+	for _, status := range []string{"Playing", "Paused"} {
+		for _, p := range players {
+			if p.status == status {
+				return p, true
+			}
+		}
+	}
+	if len(players) > 0 {
+		return players[0], true
+	}
+	return mediaPlayer{}, false
+}
+
+func mprisCall(bus, method string, args ...any) error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+	return conn.Object(bus, mprisPath).Call(mprisPlayer+"."+method, 0, args...).Err
+}
+
+func mediaPlayPause(bus string) error {
+	return mprisCall(bus, "PlayPause")
+}
+
+func mediaNext(bus string) error {
+	return mprisCall(bus, "Next")
+}
+
+func mediaPrevious(bus string) error {
+	return mprisCall(bus, "Previous")
+}
+
+// mediaSeek moves the playback position by offset, either way.
+func mediaSeek(bus string, offset time.Duration) error {
+	return mprisCall(bus, "Seek", offset.Microseconds())
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// connectivity states, from NetworkManager or our own probe
+const (
+	connectivityUnknown = "unknown"
+	connectivityNone    = "none"    // no link or route
+	connectivityLimited = "limited" // link up but no internet
+	connectivityDNS     = "dns"     // internet reachable, names don't resolve
+	connectivityPortal  = "portal"  // a captive portal answers instead
+	connectivityFull    = "full"
+)
+
+// how long the lookup and the resolver dial get
+const probeTimeout = 5 * time.Second
+
+// probeConnectivity fetches a known page and compares the body. A failed
+// lookup is told apart from no internet by dialing a public resolver. The
+// client mustn't follow redirects: a portal redirecting to its login page
+// is the thing being looked for.
+func probeConnectivity(client *HTTPClient, target, expect string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return connectivityUnknown
+	}
+	if !resolves(u.Hostname()) {
+		var d net.Dialer
+		d.Timeout = probeTimeout
+		if conn, err := d.Dial("tcp", "1.1.1.1:53"); err == nil {
+			conn.Close()
+			return connectivityDNS
+		}
+		return connectivityLimited
+	}
+
+	// the request itself goes by the shared client's timeout
+	resp, err := client.get(context.Background(), target, nil)
+	if err != nil {
+		return connectivityLimited
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode == http.StatusOK && strings.TrimSpace(string(body)) == expect {
+		return connectivityFull
+	}
+	return connectivityPortal
+}
+
+func resolves(host string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	_, err := net.DefaultResolver.LookupHost(ctx, host)
+	return err == nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const netWatchInterval = 10 * time.Second
+
+// NetworkWatcher tracks connection properties the whole bar cares about,
+// polled from NetworkManager on its own goroutine.
+type NetworkWatcher struct {
+	config       NetworkConfig
+	probe        *HTTPClient
+	mu           sync.RWMutex
+	metered      bool
+	connectivity string
+	stop         chan struct{}
+	stopOnce     sync.Once
+}
+
+func newNetworkWatcher(config NetworkConfig, client *HTTPClient) *NetworkWatcher {
+	return &NetworkWatcher{config: config, probe: client.withoutRedirects(), connectivity: connectivityUnknown, stop: make(chan struct{})}
+}
+
+// Start reads NetworkManager before the first frame; the slower probe
+// waits for the watcher goroutine.
+func (w *NetworkWatcher) Start() {
+	w.poll(false)
+	go w.run()
+}
+
+func (w *NetworkWatcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+func (w *NetworkWatcher) run() {
+	ticker := time.NewTicker(netWatchInterval)
+	defer ticker.Stop()
+
+	w.poll(true)
+	for {
+		select {
+		case <-ticker.C:
+			w.poll(true)
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+func (w *NetworkWatcher) poll(probe bool) {
+	metered := false
+	if v, err := nmProperty(nmPath, nmService, "Metered"); err == nil {
+		// NM_METERED_YES and NM_METERED_GUESS_YES
+		state, _ := v.Value().(uint32)
+		metered = state == 1 || state == 3
+	}
+
+	connectivity := connectivityUnknown
+	if v, err := nmProperty(nmPath, nmService, "Connectivity"); err == nil {
+		state, _ := v.Value().(uint32)
+		connectivity = [...]string{connectivityUnknown, connectivityNone, connectivityPortal, connectivityLimited, connectivityFull}[min(state, 4)]
+	}
+	// NetworkManager can't tell a dead resolver from no internet, and
+	// without it (or with its check disabled) there's nothing to go by
+	if probe && w.config.CheckURL != "" && (connectivity == connectivityUnknown || connectivity == connectivityLimited) {
+		connectivity = probeConnectivity(w.probe, w.config.CheckURL, w.config.CheckResponse)
+	}
+
+	w.mu.Lock()
+	w.metered = metered
+	w.connectivity = connectivity
+	w.mu.Unlock()
+}
+
+func (w *NetworkWatcher) Metered() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.metered
+}
+
+// Online is false only when the connection is known not to reach the
+// internet; unknown gets the benefit of the doubt.
+func (w *NetworkWatcher) Online() bool {
+	c := w.Connectivity()
+	return c == connectivityFull || c == connectivityUnknown
+}
+
+func (w *NetworkWatcher) Connectivity() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.connectivity
+}
+
+// paused reports whether a module's periodic refresh is held back: either
+// it is listed as network-hungry and the connection is metered, or it is
+// network-backed and there is no connectivity.
+func (m model) paused(id string, msg tea.Msg) bool {
+	if _, ok := msg.(tickMsg); !ok {
+		return false
+	}
+	if m.network.Metered() && slices.Contains(m.config.Network.PauseWhenMetered, id) {
+		return true
+	}
+	return m.stale(id)
+}
+
+// stale reports whether a network-backed module is showing what it fetched
+// before connectivity was lost.
+func (m model) stale(id string) bool {
+	return !m.network.Online() && slices.Contains(m.config.Network.Modules, id)
+}
+
+func (m model) renderStale(mod Module) segments {
+	var s segments
+	ok := m.faults.guard(mod.Name(), "render", func() {
+		if content := mod.Render(); content != "" {
+			s.add(mod.Name(), staleStyle.Render(content+" "+icon("󰅛")))
+		}
+	})
+	if !ok {
+		return m.faults.render(mod)
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+type NightLightConfig struct {
+	Backend         string `json:"backend"`
+	Temperature     int    `json:"temperature"`
+	ShowTemperature bool   `json:"show_temperature"`
+}
+
+const (
+	nightLightMin  = 1000
+	nightLightMax  = 6500
+	nightLightStep = 250
+)
+
+type nightLightStatus struct {
+	active      bool
+	temperature int
+}
+
+func nightLightState(backend string) nightLightStatus {
+	_, args, ok := findProcess(backend)
+	if !ok {
+		return nightLightStatus{}
+	}
+	status := nightLightStatus{active: true}
+
+	switch backend {
+	case "hyprsunset":
+		if out, err := hyprToolRequest("hyprsunset", "temperature"); err == nil {
+			status.temperature, _ = strconv.Atoi(out)
+		}
+	case "gammastep":
+		if i := slices.Index(args, "-O"); i >= 0 && i+1 < len(args) {
+			status.temperature, _ = strconv.Atoi(args[i+1])
+		}
+	}
+	return status
+}
+
+func startNightLight(backend string, temperature int) error {
+	var cmd *exec.Cmd
+	switch backend {
+	case "hyprsunset":
+		cmd = exec.Command("hyprsunset", "-t", strconv.Itoa(temperature))
+	case "gammastep":
+		cmd = exec.Command("gammastep", "-O", strconv.Itoa(temperature))
+	default:
+		return fmt.Errorf("unknown night light backend %q", backend)
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+func stopNightLight(backend string) error {
+	pid, _, ok := findProcess(backend)
+	if !ok {
+		return nil
+	}
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		return err
+	}
+	// wait for the old instance to release the gamma control
+	for range 20 {
+		if _, _, ok := findProcess(backend); !ok {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return nil
+}
+
+func setNightLightTemperature(backend string, temperature int) error {
+	if backend == "hyprsunset" {
+		_, err := hyprToolRequest("hyprsunset", "temperature "+strconv.Itoa(temperature))
+		return err
+	}
+	// gammastep has no IPC, restart it with the new temperature
+	if err := stopNightLight(backend); err != nil {
+		return err
+	}
+	return startNightLight(backend, temperature)
+}
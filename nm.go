@@ -0,0 +1,93 @@
+package main
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+// NetworkManager over the system bus
+
+const (
+	nmService    = "org.freedesktop.NetworkManager"
+	nmPath       = "/org/freedesktop/NetworkManager"
+	nmActiveIntf = nmService + ".Connection.Active"
+	nmDeviceIntf = nmService + ".Device"
+)
+
+type nmActiveConnection struct {
+	path     dbus.ObjectPath
+	id       string
+	kind     string
+	settings dbus.ObjectPath
+	devices  []dbus.ObjectPath
+}
+
+func nmProperty(path dbus.ObjectPath, iface, name string) (dbus.Variant, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return dbus.Variant{}, err
+	}
+	return conn.Object(nmService, path).GetProperty(iface + "." + name)
+}
+
+func nmActiveConnections() ([]nmActiveConnection, error) {
+	v, err := nmProperty(nmPath, nmService, "ActiveConnections")
+	if err != nil {
+		return nil, err
+	}
+	paths, _ := v.Value().([]dbus.ObjectPath)
+
+	active := make([]nmActiveConnection, 0, len(paths))
+	for _, path := range paths {
+		c := nmActiveConnection{path: path}
+		if v, err := nmProperty(path, nmActiveIntf, "Id"); err == nil {
+			c.id, _ = v.Value().(string)
+		}
+		if v, err := nmProperty(path, nmActiveIntf, "Type"); err == nil {
+			c.kind, _ = v.Value().(string)
+		}
+		if v, err := nmProperty(path, nmActiveIntf, "Connection"); err == nil {
+			c.settings, _ = v.Value().(dbus.ObjectPath)
+		}
+		if v, err := nmProperty(path, nmActiveIntf, "Devices"); err == nil {
+			c.devices, _ = v.Value().([]dbus.ObjectPath)
+		}
+		active = append(active, c)
+	}
+	return active, nil
+}
+
+func nmConnectionSettings(path dbus.ObjectPath) (map[string]map[string]dbus.Variant, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	var settings map[string]map[string]dbus.Variant
+	err = conn.Object(nmService, path).Call(nmService+".Settings.Connection.GetSettings", 0).Store(&settings)
+	return settings, err
+}
+
+func nmSetting(settings map[string]map[string]dbus.Variant, group, key string) string {
+	v, ok := settings[group][key]
+	if !ok {
+		return ""
+	}
+	s, _ := v.Value().(string)
+	return s
+}
+
+func nmDeviceInterface(path dbus.ObjectPath) string {
+	v, err := nmProperty(path, nmDeviceIntf, "Interface")
+	if err != nil {
+		return ""
+	}
+	s, _ := v.Value().(string)
+	return s
+}
+
+func nmDeactivate(active dbus.ObjectPath) error {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return err
+	}
+	return conn.Object(nmService, nmPath).Call(nmService+".DeactivateConnection", 0, active).Err
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// notification history from the running notification daemon. mako and
+// dunst both print D-Bus style variant dicts:
+//   {"type": "aa{sv}", "data": [[{"summary": {"type": "s", "data": "..."}, ...}]]}
+// with the app name under "app-name" (mako) or "appname" (dunst).
+
+// NotificationsConfig.Backend is "mako", "dunst" or "builtin", which makes
+// the bar the notification daemon itself and shows each new notification
+// on the bar for TransientSeconds (unless the sender asks otherwise).
+type NotificationsConfig struct {
+	Backend          string `json:"backend"`
+	Limit            int    `json:"limit"`
+	TransientSeconds int    `json:"transient_seconds"`
+}
+
+type notification struct {
+	id      int
+	app     string
+	summary string
+	body    string
+	visible bool
+
+	// set by the builtin daemon
+	at            time.Time
+	urgency       int
+	timeout       time.Duration
+	defaultAction bool
+}
+
+func notificationBackend(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if _, _, ok := findProcess("dunst"); ok {
+		return "dunst"
+	}
+	return "mako"
+}
+
+func fetchNotifications(backend string) ([]notification, error) {
+	switch backend {
+	case "dunst":
+		return notificationList("dunstctl", "history")
+	case "mako":
+		visible, err := notificationList("makoctl", "list")
+		if err != nil {
+			return nil, err
+		}
+		for i := range visible {
+			visible[i].visible = true
+		}
+		history, _ := notificationList("makoctl", "history")
+		return append(visible, history...), nil
+	}
+	return nil, fmt.Errorf("unknown notification backend %q", backend)
+}
+
+func notificationList(command ...string) ([]notification, error) {
+	out, err := exec.Command(command[0], command[1:]...).Output()
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Data [][]map[string]struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, err
+	}
+
+	var notifications []notification
+	for _, group := range list.Data {
+		for _, fields := range group {
+			str := func(key string) string {
+				var s string
+				json.Unmarshal(fields[key].Data, &s)
+				return s
+			}
+			var id int
+			json.Unmarshal(fields["id"].Data, &id)
+			app := str("app-name")
+			if app == "" {
+				app = str("appname")
+			}
+			notifications = append(notifications, notification{
+				id:      id,
+				app:     app,
+				summary: str("summary"),
+				body:    str("body"),
+			})
+		}
+	}
+	// newest first
+	sort.SliceStable(notifications, func(i, j int) bool { return notifications[i].id > notifications[j].id })
+	return notifications, nil
+}
+
+// invokeNotification runs a notification's default action. dunst can only
+// act on displayed notifications, so a history entry is shown again first.
+func invokeNotification(backend string, n notification) error {
+	id := strconv.Itoa(n.id)
+	switch backend {
+	case "dunst":
+		if err := exec.Command("dunstctl", "history-pop", id).Run(); err != nil {
+			return err
+		}
+		return exec.Command("dunstctl", "action", "0").Run()
+	case "mako":
+		if !n.visible {
+			return fmt.Errorf("mako can't invoke actions of dismissed notifications")
+		}
+		return exec.Command("makoctl", "invoke", "-n", id).Run()
+	}
+	return fmt.Errorf("unknown notification backend %q", backend)
+}
+
+func dismissNotification(backend string, n notification) error {
+	id := strconv.Itoa(n.id)
+	switch backend {
+	case "dunst":
+		return exec.Command("dunstctl", "history-rm", id).Run()
+	case "mako":
+		if !n.visible {
+			return nil
+		}
+		return exec.Command("makoctl", "dismiss", "-n", id).Run()
+	}
+	return fmt.Errorf("unknown notification backend %q", backend)
+}
+
+func clearNotifications(backend string) error {
+	switch backend {
+	case "dunst":
+		exec.Command("dunstctl", "close-all").Run()
+		return exec.Command("dunstctl", "history-clear").Run()
+	case "mako":
+		return exec.Command("makoctl", "dismiss", "--all").Run()
+	}
+	return fmt.Errorf("unknown notification backend %q", backend)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// notificationDaemon is the bar's own org.freedesktop.Notifications
+// server, for setups without mako or dunst. It keeps a bounded history and
+// hands every new notification to the module over a channel.
+
+const (
+	notifyPath      = "/org/freedesktop/Notifications"
+	notifyInterface = "org.freedesktop.Notifications"
+	notifyHistory   = 50
+
+	// NotificationClosed reasons
+	notifyExpired   = 1
+	notifyDismissed = 2
+	notifyClosed    = 3
+)
+
+type notificationDaemon struct {
+	conn     *dbus.Conn
+	mu       sync.Mutex
+	nextID   uint32
+	history  []notification
+	arrivals chan notification
+}
+
+func startNotificationDaemon(lifetime context.Context) (*notificationDaemon, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, err
+	}
+	d := &notificationDaemon{
+		conn:     conn,
+		arrivals: make(chan notification, 16),
+	}
+	if err := conn.Export(d, notifyPath, notifyInterface); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reply, err := conn.RequestName(notifyInterface, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		conn.Close()
+		return nil, fmt.Errorf("another notification daemon owns %s", notifyInterface)
+	}
+
+	go func() {
+		<-lifetime.Done()
+		conn.Close()
+	}()
+	return d, nil
+}
+
+func (d *notificationDaemon) GetCapabilities() ([]string, *dbus.Error) {
+	return []string{"body", "actions"}, nil
+}
+
+func (d *notificationDaemon) GetServerInformation() (string, string, string, string, *dbus.Error) {
+	return "tui-bar", "tui-bar", "1.0", "1.2", nil
+}
+
+func (d *notificationDaemon) Notify(app string, replaces uint32, appIcon, summary, body string,
+	actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	n := notification{
+		app:     app,
+		summary: summary,
+		body:    body,
+		visible: true,
+		at:      time.Now(),
+	}
+	if v, ok := hints["urgency"]; ok {
+		if urgency, ok := v.Value().(byte); ok {
+			n.urgency = int(urgency)
+		}
+	}
+	// actions alternate key, label
+	for i := 0; i+1 < len(actions); i += 2 {
+		if actions[i] == "default" {
+			n.defaultAction = true
+		}
+	}
+	if timeout > 0 {
+		n.timeout = time.Duration(timeout) * time.Millisecond
+	}
+
+	d.mu.Lock()
+	if replaces != 0 {
+		n.id = int(replaces)
+		d.history = slices.DeleteFunc(d.history, func(old notification) bool { return old.id == n.id })
+	} else {
+		d.nextID++
+		n.id = int(d.nextID)
+	}
+	d.history = append([]notification{n}, d.history...)
+	if len(d.history) > notifyHistory {
+		d.history = d.history[:notifyHistory]
+	}
+	d.mu.Unlock()
+
+	select {
+	case d.arrivals <- n:
+	default:
+	}
+	return uint32(n.id), nil
+}
+
+func (d *notificationDaemon) CloseNotification(id uint32) *dbus.Error {
+	d.remove(int(id), notifyClosed)
+	return nil
+}
+
+func (d *notificationDaemon) list() []notification {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.history)
+}
+
+func (d *notificationDaemon) remove(id int, reason uint32) {
+	d.mu.Lock()
+	d.history = slices.DeleteFunc(d.history, func(n notification) bool { return n.id == id })
+	d.mu.Unlock()
+	d.conn.Emit(notifyPath, notifyInterface+".NotificationClosed", uint32(id), reason)
+}
+
+func (d *notificationDaemon) invoke(n notification) error {
+	if !n.defaultAction {
+		return fmt.Errorf("%s notification has no default action", n.app)
+	}
+	if err := d.conn.Emit(notifyPath, notifyInterface+".ActionInvoked", uint32(n.id), "default"); err != nil {
+		return err
+	}
+	d.remove(n.id, notifyDismissed)
+	return nil
+}
+
+func (d *notificationDaemon) clear() {
+	for _, n := range d.list() {
+		d.remove(n.id, notifyDismissed)
+	}
+}
+
+// expire marks a notification as no longer shown on the bar; it stays in
+// the history.
+func (d *notificationDaemon) expire(id int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := range d.history {
+		if d.history[i].id == id && d.history[i].visible {
+			d.history[i].visible = false
+			d.conn.Emit(notifyPath, notifyInterface+".NotificationClosed", uint32(id), uint32(notifyExpired))
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+type windowOccupancy struct {
+	windows map[string]int
+	classes map[string]string
+}
+
+func newWindowOccupancy() *windowOccupancy {
+	return &windowOccupancy{windows: make(map[string]int), classes: make(map[string]string)}
+}
+
+func (o *windowOccupancy) reset(windows []HyprlandWindow) {
+	o.windows = make(map[string]int, len(windows))
+	o.classes = make(map[string]string, len(windows))
+	for _, win := range windows {
+		addr := normalizeAddress(win.Address)
+		o.windows[addr] = win.Workspace.ID
+		o.classes[addr] = win.Class
+	}
+}
+
+func (o *windowOccupancy) counts() map[int]int {
+	counts := make(map[int]int)
+	for _, ws := range o.windows {
+		counts[ws]++
+	}
+	return counts
+}
+
+// workspaceOf returns the workspace a window is on, if it's known.
+func (o *windowOccupancy) workspaceOf(addr string) (int, bool) {
+	ws, ok := o.windows[normalizeAddress(addr)]
+	return ws, ok
+}
+
+// dominant returns the most common window class on each workspace, ties
+// going to the alphabetically first class so labels don't flicker.
+func (o *windowOccupancy) dominant() map[int]string {
+	perWorkspace := make(map[int]map[string]int)
+	for addr, ws := range o.windows {
+		class := o.classes[addr]
+		if class == "" {
+			continue
+		}
+		if perWorkspace[ws] == nil {
+			perWorkspace[ws] = make(map[string]int)
+		}
+		perWorkspace[ws][class]++
+	}
+
+	dominant := make(map[int]string, len(perWorkspace))
+	for ws, classes := range perWorkspace {
+		best, bestCount := "", 0
+		for class, n := range classes {
+			if n > bestCount || n == bestCount && class < best {
+				best, bestCount = class, n
+			}
+		}
+		dominant[ws] = best
+	}
+	return dominant
+}
+
+func (o *windowOccupancy) apply(event HyprlandEvent, resolve func(name string) int) {
+	if len(event.Data) == 0 {
+		return
+	}
+	addr := normalizeAddress(event.Data[0])
+
+	switch event.Type {
+	case "openwindow":
+		if len(event.Data) >= 2 {
+			o.windows[addr] = resolve(event.Data[1])
+		}
+		if len(event.Data) >= 3 {
+			o.classes[addr] = event.Data[2]
+		}
+	case "closewindow":
+		delete(o.windows, addr)
+		delete(o.classes, addr)
+	case "movewindow":
+		// address,name before v2; address,id,name since
+		switch len(event.Data) {
+		case 2:
+			o.windows[addr] = resolve(event.Data[1])
+		case 3:
+			if id, err := strconv.Atoi(event.Data[1]); err == nil {
+				o.windows[addr] = id
+			}
+		}
+	}
+}
+
+func normalizeAddress(addr string) string {
+	return strings.TrimPrefix(addr, "0x")
+}
+
+var superscriptDigits = []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+
+func superscript(n int) string {
+	var b strings.Builder
+	for _, r := range strconv.Itoa(n) {
+		b.WriteRune(superscriptDigits[r-'0'])
+	}
+	return b.String()
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Plugins are external executables speaking JSON lines over stdio. Every
+// line on the plugin's stdout replaces its state:
+//   {"text": "󰎆 3", "class": "warning", "visible": true}
+// and mouse events on the module are written to its stdin:
+//   {"event": "click", "button": "left", "modifiers": ["shift"]}
+//   {"event": "scroll", "direction": "up"}
+// A line of the form {"hyprctl": "dispatch workspace 3"} is an action
+// instead: the command goes to Hyprland if config's hyprctl.allow permits.
+// API keys and the like are passed in the environment from "secrets",
+// mapping variable names to secret references (see secrets.go).
+
+type PluginConfig struct {
+	Name     string            `json:"name"`
+	Command  []string          `json:"command"`
+	Position string            `json:"position"`
+	Restart  bool              `json:"restart"`
+	Secrets  map[string]string `json:"secrets"`
+}
+
+type pluginState struct {
+	Text    string `json:"text"`
+	Class   string `json:"class"`
+	Visible *bool  `json:"visible"`
+	Hyprctl string `json:"hyprctl,omitempty"`
+}
+
+type pluginEvent struct {
+	Event     string   `json:"event"`
+	Button    string   `json:"button,omitempty"`
+	Direction string   `json:"direction,omitempty"`
+	Modifiers []string `json:"modifiers,omitempty"`
+}
+
+type pluginStateMsg pluginState
+type pluginActionMsg struct{ hyprctl string }
+type pluginExitMsg struct{ err error }
+type pluginRestartMsg struct{}
+
+type PluginModule struct {
+	ctx     *moduleContext
+	config  PluginConfig
+	state   pluginState
+	updates chan tea.Msg
+	stdin   io.WriteCloser
+	mu      sync.Mutex
+	backoff time.Duration
+	ready   bool
+}
+
+func newPluginModule(ctx *moduleContext, config PluginConfig) *PluginModule {
+	return &PluginModule{
+		ctx:     ctx,
+		config:  config,
+		backoff: time.Second,
+	}
+}
+
+func (m *PluginModule) Name() string {
+	return "plugin:" + m.config.Name
+}
+
+func (m *PluginModule) Init() tea.Cmd {
+	return m.start()
+}
+
+func (m *PluginModule) start() tea.Cmd {
+	if len(m.config.Command) == 0 {
+		m.state = pluginState{Text: "no command", Class: "critical"}
+		m.ready = true
+		return nil
+	}
+
+	env, err := secretEnv(m.config.Secrets)
+	if err != nil {
+		return m.fail(err)
+	}
+
+	cmd := exec.CommandContext(m.ctx.lifetime, m.config.Command[0], m.config.Command[1:]...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGTERM}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return m.fail(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return m.fail(err)
+	}
+	if err := cmd.Start(); err != nil {
+		return m.fail(err)
+	}
+
+	m.mu.Lock()
+	m.stdin = stdin
+	m.mu.Unlock()
+
+	updates := make(chan tea.Msg, 16)
+	m.updates = updates
+	done := m.ctx.lifetime.Done()
+	go func() {
+		defer close(updates)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			var state pluginState
+			if err := json.Unmarshal(scanner.Bytes(), &state); err != nil {
+				state = pluginState{Text: scanner.Text()}
+			}
+			var msg tea.Msg = pluginStateMsg(state)
+			if state.Hyprctl != "" {
+				msg = pluginActionMsg{hyprctl: state.Hyprctl}
+			}
+			select {
+			case updates <- msg:
+			case <-done:
+			}
+		}
+		err := cmd.Wait()
+		select {
+		case updates <- pluginExitMsg{err: err}:
+		case <-done:
+		}
+	}()
+	return m.wait()
+}
+
+func (m *PluginModule) fail(err error) tea.Cmd {
+	log.Printf("plugin %s: %v", m.config.Name, err)
+	m.state = pluginState{Text: "plugin error", Class: "critical"}
+	m.ready = true
+	return m.scheduleRestart()
+}
+
+func (m *PluginModule) wait() tea.Cmd {
+	updates := m.updates
+	return moduleCmd(m.Name(), func() tea.Msg {
+		msg, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return msg
+	})
+}
+
+func (m *PluginModule) scheduleRestart() tea.Cmd {
+	if !m.config.Restart || m.ctx.lifetime.Err() != nil {
+		return nil
+	}
+	delay := m.backoff
+	m.backoff = min(m.backoff*2, time.Minute)
+	id := m.Name()
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return moduleMsg{id: id, msg: pluginRestartMsg{}}
+	})
+}
+
+func (m *PluginModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case pluginStateMsg:
+		m.state = pluginState(msg)
+		m.ready = true
+		m.backoff = time.Second
+		return m.wait()
+	case pluginActionMsg:
+		return tea.Batch(m.runHyprctl(msg.hyprctl), m.wait())
+	case pluginExitMsg:
+		if msg.err != nil {
+			log.Printf("plugin %s exited: %v", m.config.Name, msg.err)
+		}
+		m.mu.Lock()
+		m.stdin = nil
+		m.mu.Unlock()
+		return m.scheduleRestart()
+	case pluginRestartMsg:
+		return m.start()
+	}
+	return nil
+}
+
+func (m *PluginModule) runHyprctl(command string) tea.Cmd {
+	hc := m.ctx.hypr
+	if hc == nil {
+		return nil
+	}
+	name := m.config.Name
+	return func() tea.Msg {
+		if _, err := hc.Raw(command); err != nil {
+			log.Printf("plugin %s: %v", name, err)
+		}
+		return nil
+	}
+}
+
+func (m *PluginModule) Ready() bool {
+	return m.ready
+}
+
+func (m *PluginModule) Vars(vars exprVars) {
+	vars[m.Name()+".text"] = m.state.Text
+	vars[m.Name()+".class"] = m.state.Class
+}
+
+func (m *PluginModule) Render() string {
+	if !m.ready {
+		return placeholder("󰐱")
+	}
+	if m.state.Visible != nil && !*m.state.Visible {
+		return ""
+	}
+	return m.state.Text
+}
+
+func (m *PluginModule) Style() lipgloss.Style {
+	return classStyle(m.state.Class)
+}
+
+func (m *PluginModule) HandleMouse(target string, msg tea.MouseMsg) tea.Cmd {
+	event := pluginEvent{Event: "click"}
+	switch msg.Type {
+	case tea.MouseLeft:
+		event.Button = "left"
+	case tea.MouseRight:
+		event.Button = "right"
+	case tea.MouseMiddle:
+		event.Button = "middle"
+	case tea.MouseWheelUp:
+		event = pluginEvent{Event: "scroll", Direction: "up"}
+	case tea.MouseWheelDown:
+		event = pluginEvent{Event: "scroll", Direction: "down"}
+	default:
+		return nil
+	}
+	if msg.Shift {
+		event.Modifiers = append(event.Modifiers, "shift")
+	}
+	if msg.Ctrl {
+		event.Modifiers = append(event.Modifiers, "ctrl")
+	}
+	if msg.Alt {
+		event.Modifiers = append(event.Modifiers, "alt")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stdin == nil {
+		return nil
+	}
+	data, _ := json.Marshal(event)
+	if _, err := fmt.Fprintf(m.stdin, "%s\n", data); err != nil {
+		log.Printf("plugin %s: %v", m.config.Name, err)
+	}
+	return nil
+}
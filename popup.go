@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// popupModule is implemented by modules that can open a popup below (or
+// above) the bar. Each row is a single line with its own hit zones, which
+// are delivered to the module's HandleMouse like bar targets.
+type popupModule interface {
+	Popup() []segments
+}
+
+// popupKeyHandler is implemented by popups that take keyboard input while
+// open. HandleKey reports whether it consumed the key.
+type popupKeyHandler interface {
+	HandleKey(msg tea.KeyMsg) (tea.Cmd, bool)
+}
+
+type popupToggleMsg struct{ id string }
+
+// closePopup closes whichever popup is open.
+func closePopup() tea.Cmd {
+	return togglePopup("")
+}
+
+// popupMsg tells a module its popup was opened or closed.
+type popupMsg struct{ open bool }
+
+func togglePopup(id string) tea.Cmd {
+	return func() tea.Msg {
+		return popupToggleMsg{id: id}
+	}
+}
+
+func (m model) setPopup(id string) (model, tea.Cmd) {
+	if id == m.popup {
+		return m, nil
+	}
+	var cmds []tea.Cmd
+	if mod, ok := m.moduleByID[m.popup]; ok {
+		cmds = append(cmds, m.faults.update(mod, popupMsg{open: false}))
+	}
+	m.popup = id
+	if mod, ok := m.moduleByID[id]; ok {
+		cmds = append(cmds, m.faults.update(mod, popupMsg{open: true}))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) popupKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	h, ok := m.moduleByID[m.popup].(popupKeyHandler)
+	if !ok {
+		return nil, false
+	}
+	var cmd tea.Cmd
+	var handled bool
+	m.faults.guard(m.popup, "key", func() { cmd, handled = h.HandleKey(msg) })
+	return m.faults.guardCmd(m.popup, cmd), handled
+}
+
+func (m model) renderPopup(zones []hitZone) ([]string, [][]hitZone, int) {
+	mod, ok := m.moduleByID[m.popup]
+	if !ok {
+		return nil, nil, 0
+	}
+	p, ok := mod.(popupModule)
+	if !ok {
+		return nil, nil, 0
+	}
+	var rows []segments
+	if !m.faults.guard(m.popup, "popup", func() { rows = p.Popup() }) || len(rows) == 0 {
+		return nil, nil, 0
+	}
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, row.width)
+	}
+
+	// line the popup up with its module, keeping it on screen
+	x := 0
+	for _, z := range zones {
+		if id, _ := splitTarget(z.target); id == m.popup {
+			x = z.start
+			break
+		}
+	}
+	x = max(min(x, m.width-width), 0)
+
+	lines := make([]string, len(rows))
+	rowZones := make([][]hitZone, len(rows))
+	for i, row := range rows {
+		lines[i] = strings.Repeat(" ", x) + popupStyle.Width(width).Render(row.render())
+		rowZones[i] = row.zones
+	}
+	return lines, rowZones, x
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	_ "net/http/pprof"
+)
+
+// startPprof exposes net/http/pprof on addr for diagnosing memory growth
+// and goroutine leaks in a long-running bar.
+func startPprof(addr string) {
+	if addr == "" {
+		return
+	}
+	go func() {
+		if err := http.ListenAndServe(addr, nil); err != nil {
+			log.Printf("pprof disabled: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PresentationConfig: presentation mode hides the Hide modules while
+// sharing the screen. With Auto it switches itself on during a Hyprland
+// screencast or while one of Recorders runs.
+type PresentationConfig struct {
+	Auto      bool     `json:"auto"`
+	Hide      []string `json:"hide"`
+	Recorders []string `json:"recorders"`
+}
+
+type recordingMsg bool
+
+func detectRecording(recorders []string) tea.Cmd {
+	if len(recorders) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		_, ok := findAnyProcess(recorders)
+		return recordingMsg(ok)
+	}
+}
+
+// checkRecording polls for recorders every few seconds.
+func (m model) checkRecording(now time.Time) tea.Cmd {
+	if !m.config.Presentation.Auto || now.Second()%5 != 0 {
+		return nil
+	}
+	return detectRecording(m.config.Presentation.Recorders)
+}
+
+// observeScreencast follows Hyprland's screencast>>state,owner events.
+func (m model) observeScreencast(events []hyprEventMsg) model {
+	for _, e := range events {
+		if e.Type == "screencast" && len(e.Data) > 0 {
+			m.screencast = e.Data[0] == "1"
+		}
+	}
+	return m
+}
+
+func (m model) presenting() bool {
+	return m.present || m.config.Presentation.Auto && (m.screencast || m.recording)
+}
+
+func (m model) presentationHidden(id string) bool {
+	return m.presenting() && slices.Contains(m.config.Presentation.Hide, id)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// findProcess looks for a running process whose executable is name and
+// returns its pid and argv.
+func findProcess(name string) (int, []string, bool) {
+	procs, _ := filepath.Glob("/proc/[0-9]*/cmdline")
+	for _, path := range procs {
+		data, err := os.ReadFile(path)
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+		if filepath.Base(args[0]) != name {
+			continue
+		}
+		pid, _ := strconv.Atoi(filepath.Base(filepath.Dir(path)))
+		return pid, args, true
+	}
+	return 0, nil, false
+}
+
+// findAnyProcess reports which of names, if any, is running, in one pass
+// over /proc.
+func findAnyProcess(names []string) (string, bool) {
+	procs, _ := filepath.Glob("/proc/[0-9]*/cmdline")
+	for _, path := range procs {
+		data, err := os.ReadFile(path)
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		exe, _, _ := strings.Cut(string(data), "\x00")
+		if name := filepath.Base(exe); slices.Contains(names, name) {
+			return name, true
+		}
+	}
+	return "", false
+}
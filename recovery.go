@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"runtime/debug"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// per-module panic recovery: a module that panics is marked errored and
+// left out from then on while the rest of the bar keeps running
+
+type moduleFaults struct {
+	mu     sync.Mutex
+	errors map[string]string
+}
+
+func newModuleFaults() *moduleFaults {
+	return &moduleFaults{errors: make(map[string]string)}
+}
+
+func (f *moduleFaults) failed(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.errors[id]
+	return ok
+}
+
+func (f *moduleFaults) snapshot() map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return maps.Clone(f.errors)
+}
+
+// guard runs fn on behalf of module id and reports whether it completed.
+func (f *moduleFaults) guard(id, stage string, fn func()) (ok bool) {
+	if f.failed(id) {
+		return false
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("module %s panicked in %s: %v\n%s", id, stage, r, debug.Stack())
+			f.mu.Lock()
+			f.errors[id] = fmt.Sprint(r)
+			f.mu.Unlock()
+			ok = false
+		}
+	}()
+	fn()
+	return true
+}
+
+// guardCmd wraps a module's command so a panic in its collector doesn't
+// take the program down; batched commands are wrapped as they unfold.
+func (f *moduleFaults) guardCmd(id string, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		var msg tea.Msg
+		f.guard(id, "command", func() { msg = cmd() })
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for i := range batch {
+				batch[i] = f.guardCmd(id, batch[i])
+			}
+		}
+		return msg
+	}
+}
+
+func (f *moduleFaults) init(mod Module) tea.Cmd {
+	var cmd tea.Cmd
+	f.guard(mod.Name(), "init", func() { cmd = mod.Init() })
+	return f.guardCmd(mod.Name(), cmd)
+}
+
+func (f *moduleFaults) update(mod Module, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	f.guard(mod.Name(), "update", func() { cmd = mod.Update(msg) })
+	return f.guardCmd(mod.Name(), cmd)
+}
+
+func (f *moduleFaults) render(mod Module) segments {
+	var s segments
+	if f.guard(mod.Name(), "render", func() { s = renderModule(mod) }) {
+		return s
+	}
+	s = segments{}
+	s.add(mod.Name(), criticalStyle.Render(icon("󰀦")+" "+mod.Name()))
+	return s
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+type ModuleFactory func(ctx *moduleContext) Module
+
+var moduleRegistry = make(map[string]ModuleFactory)
+
+// RegisterModule makes a module available to the config's "modules" list.
+// Modules call it from init() so the bar never has to know about them.
+func RegisterModule(name string, factory ModuleFactory) {
+	if _, exists := moduleRegistry[name]; exists {
+		panic(fmt.Sprintf("module %q registered twice", name))
+	}
+	moduleRegistry[name] = factory
+}
+
+func registeredModules() []string {
+	names := make([]string, 0, len(moduleRegistry))
+	for name := range moduleRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
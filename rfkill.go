@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+type rfkillDevice struct {
+	index int
+	kind  string
+	name  string
+	soft  bool
+	hard  bool
+}
+
+func (d rfkillDevice) blocked() bool {
+	return d.soft || d.hard
+}
+
+func listRfkill() []rfkillDevice {
+	dirs, _ := filepath.Glob("/sys/class/rfkill/rfkill*")
+	devices := make([]rfkillDevice, 0, len(dirs))
+	for _, dir := range dirs {
+		index, err := strconv.Atoi(readSysfsString(dir, "index"))
+		if err != nil {
+			continue
+		}
+		devices = append(devices, rfkillDevice{
+			index: index,
+			kind:  readSysfsString(dir, "type"),
+			name:  readSysfsString(dir, "name"),
+			soft:  readSysfsString(dir, "soft") == "1",
+			hard:  readSysfsString(dir, "hard") == "1",
+		})
+	}
+	return devices
+}
+
+// rfkill event as defined in linux/rfkill.h
+const (
+	rfkillTypeWLAN      = 1
+	rfkillTypeBluetooth = 2
+	rfkillOpChange      = 2
+	rfkillOpChangeAll   = 3
+)
+
+var rfkillTypes = map[string]uint8{
+	"wlan":      rfkillTypeWLAN,
+	"bluetooth": rfkillTypeBluetooth,
+}
+
+func writeRfkillEvent(index uint32, kind, op uint8, block bool) error {
+	f, err := os.OpenFile("/dev/rfkill", os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	event := make([]byte, 8)
+	binary.NativeEndian.PutUint32(event[0:4], index)
+	event[4] = kind
+	event[5] = op
+	if block {
+		event[6] = 1
+	}
+	_, err = f.Write(event)
+	return err
+}
+
+// setRfkill soft-blocks or unblocks a single device.
+func setRfkill(index int, block bool) error {
+	return writeRfkillEvent(uint32(index), 0, rfkillOpChange, block)
+}
+
+// setRfkillType soft-blocks or unblocks every device of a type, e.g. "wlan".
+func setRfkillType(kind string, block bool) error {
+	t, ok := rfkillTypes[kind]
+	if !ok {
+		return fmt.Errorf("unknown rfkill type %q", kind)
+	}
+	return writeRfkillEvent(0, t, rfkillOpChangeAll, block)
+}
@@ -0,0 +1,42 @@
+package main
+
+import "time"
+
+func collectVars(modules []Module, faults *moduleFaults) exprVars {
+	now := time.Now()
+	vars := exprVars{
+		"time.hour":   now.Hour(),
+		"time.minute": now.Minute(),
+	}
+	for _, mod := range modules {
+		if p, ok := mod.(varProvider); ok {
+			faults.guard(mod.Name(), "vars", func() { p.Vars(vars) })
+		}
+	}
+	return vars
+}
+
+func (m model) ruleVars() exprVars {
+	vars := collectVars(m.modules, m.faults)
+	vars["terminal.width"] = m.width
+	vars["terminal.height"] = m.height
+	vars["network.online"] = m.network.Online()
+	vars["network.metered"] = m.network.Metered()
+	vars["network.connectivity"] = m.network.Connectivity()
+	vars["focus"] = m.focus
+	vars["presentation"] = m.presenting()
+	vars["workspace.id"] = m.workspace.id
+	vars["workspace.name"] = m.workspace.name
+	return vars
+}
+
+func (m model) moduleVisible(name string, vars exprVars) bool {
+	if m.presentationHidden(name) || !m.workspaceVisible(name) {
+		return false
+	}
+	rule, ok := m.config.visibilityRules[name]
+	if !ok {
+		return true
+	}
+	return evalBool(rule, vars)
+}
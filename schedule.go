@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScheduleConfig is a recurring timetable. Each entry fires either on a
+// cron spec ("30 9 * * mon-fri") or at fixed Times ("05:12") on Days (a
+// cron day-of-week field, every day when empty), and may run Command.
+type ScheduleConfig struct {
+	Entries []ScheduleEntry `json:"entries"`
+}
+
+type ScheduleEntry struct {
+	Name    string   `json:"name"`
+	Cron    string   `json:"cron"`
+	Times   []string `json:"times"`
+	Days    string   `json:"days"`
+	Command []string `json:"command"`
+}
+
+// cronSpec holds the allowed values of the five cron fields.
+type cronSpec struct {
+	minute, hour, dom, month, dow [60]bool
+	anyDom, anyDow                bool
+}
+
+var cronDayNames = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+func parseCron(spec string) (*cronSpec, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: want 5 fields", spec)
+	}
+	c := &cronSpec{anyDom: fields[2] == "*", anyDow: fields[4] == "*"}
+	for i, f := range []struct {
+		set      *[60]bool
+		min, max int
+	}{{&c.minute, 0, 59}, {&c.hour, 0, 23}, {&c.dom, 1, 31}, {&c.month, 1, 12}, {&c.dow, 0, 7}} {
+		if err := parseCronField(fields[i], f.min, f.max, f.set); err != nil {
+			return nil, fmt.Errorf("cron %q: %v", spec, err)
+		}
+	}
+	// 7 is Sunday too
+	c.dow[0] = c.dow[0] || c.dow[7]
+	return c, nil
+}
+
+func parseCronField(field string, lo, hi int, set *[60]bool) error {
+	for _, part := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step <= 0 {
+				return fmt.Errorf("bad step %q", part)
+			}
+		}
+
+		from, to := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = cronValue(a); err != nil {
+				return err
+			}
+			to = from
+			if isRange {
+				if to, err = cronValue(b); err != nil {
+					return err
+				}
+			} else if hasStep {
+				to = hi
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return fmt.Errorf("%q out of range %d-%d", part, lo, hi)
+		}
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+func cronValue(s string) (int, error) {
+	if d, ok := cronDayNames[strings.ToLower(s)]; ok {
+		return d, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("bad value %q", s)
+	}
+	return v, nil
+}
+
+func (c *cronSpec) matchesDay(t time.Time) bool {
+	if !c.month[t.Month()] {
+		return false
+	}
+	dom, dow := c.dom[t.Day()], c.dow[t.Weekday()]
+	// like cron: with both restricted, either may match
+	switch {
+	case c.anyDom:
+		return dow
+	case c.anyDow:
+		return dom
+	}
+	return dom || dow
+}
+
+// next is the first matching minute after t, within a year.
+func (c *cronSpec) next(t time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for i := 0; i < 366; i++ {
+		if c.matchesDay(day) {
+			for h := 0; h < 24; h++ {
+				if !c.hour[h] {
+					continue
+				}
+				for min := 0; min < 60; min++ {
+					at := time.Date(day.Year(), day.Month(), day.Day(), h, min, 0, 0, day.Location())
+					if c.minute[min] && !at.Before(t) {
+						return at, true
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}, false
+}
+
+// scheduleSpecs turns an entry into cron specs, one per fixed time.
+func scheduleSpecs(e ScheduleEntry) ([]*cronSpec, error) {
+	if e.Cron != "" {
+		c, err := parseCron(e.Cron)
+		if err != nil {
+			return nil, err
+		}
+		return []*cronSpec{c}, nil
+	}
+	days := e.Days
+	if days == "" {
+		days = "*"
+	}
+	var specs []*cronSpec
+	for _, at := range e.Times {
+		t, err := time.Parse("15:04", at)
+		if err != nil {
+			return nil, fmt.Errorf("bad time %q", at)
+		}
+		c, err := parseCron(fmt.Sprintf("%d %d * * %s", t.Minute(), t.Hour(), days))
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, c)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no cron or times")
+	}
+	return specs, nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"go.starlark.net/starlark"
+)
+
+// Script modules are Starlark files defining render(vars). vars holds the
+// same values visibility rules see ("cpu", "battery.level", ...), and
+// render returns either a string or a dict with "text", "class" and
+// "visible" keys, so thresholds can be expressed in the script:
+//
+//   def render(vars):
+//       load = vars["cpu"]
+//       return {"text": "%d%%" % load, "class": "critical" if load > 90 else ""}
+
+const (
+	scriptMaxSteps = 1000000
+	scriptTimeout  = 100 * time.Millisecond
+)
+
+type ScriptConfig struct {
+	Name     string `json:"name"`
+	File     string `json:"file"`
+	Interval int    `json:"interval"`
+	Position string `json:"position"`
+}
+
+type scriptResult struct {
+	text    string
+	class   string
+	visible bool
+	err     error
+}
+
+type ScriptModule struct {
+	ctx     *moduleContext
+	config  ScriptConfig
+	render  starlark.Value
+	result  scriptResult
+	ready   bool
+	elapsed time.Duration
+}
+
+func newScriptModule(ctx *moduleContext, config ScriptConfig) *ScriptModule {
+	if config.Interval <= 0 {
+		config.Interval = 5
+	}
+	return &ScriptModule{ctx: ctx, config: config}
+}
+
+func (m *ScriptModule) Name() string {
+	return "script:" + m.config.Name
+}
+
+func newScriptThread(name string) (*starlark.Thread, func()) {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			log.Printf("script %s: %s", name, msg)
+		},
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+	timer := time.AfterFunc(scriptTimeout, func() {
+		thread.Cancel("timeout")
+	})
+	return thread, func() { timer.Stop() }
+}
+
+func (m *ScriptModule) Init() tea.Cmd {
+	path := expandHome(m.config.File)
+	src, err := os.ReadFile(path)
+	if err != nil {
+		m.result = scriptResult{err: err}
+		m.ready = true
+		return nil
+	}
+
+	thread, done := newScriptThread(m.config.Name)
+	globals, err := starlark.ExecFile(thread, path, src, nil)
+	done()
+	if err != nil {
+		m.result = scriptResult{err: err}
+		m.ready = true
+		return nil
+	}
+
+	render, ok := globals["render"]
+	if !ok {
+		m.result = scriptResult{err: fmt.Errorf("%s does not define render(vars)", path)}
+		m.ready = true
+		return nil
+	}
+	globals.Freeze()
+	m.render = render
+	return m.eval()
+}
+
+func (m *ScriptModule) eval() tea.Cmd {
+	if m.render == nil {
+		return nil
+	}
+	render, name := m.render, m.config.Name
+	vars := scriptVars(m.ctx.vars())
+	return moduleCmd(m.Name(), func() tea.Msg {
+		thread, done := newScriptThread(name)
+		defer done()
+
+		value, err := starlark.Call(thread, render, starlark.Tuple{vars}, nil)
+		if err != nil {
+			return scriptResult{err: err}
+		}
+		return toScriptResult(value)
+	})
+}
+
+func (m *ScriptModule) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case tickMsg:
+		m.elapsed += time.Second
+		if m.elapsed < time.Duration(m.config.Interval)*time.Second {
+			return nil
+		}
+		m.elapsed = 0
+		return m.eval()
+	case scriptResult:
+		if msg.err != nil {
+			log.Printf("script %s: %v", m.config.Name, msg.err)
+		}
+		m.result = msg
+		m.ready = true
+	}
+	return nil
+}
+
+func (m *ScriptModule) Ready() bool {
+	return m.ready
+}
+
+func (m *ScriptModule) Render() string {
+	if !m.ready {
+		return placeholder("󰅩")
+	}
+	if m.result.err != nil {
+		return icon("󰀦") + " " + m.config.Name
+	}
+	if !m.result.visible {
+		return ""
+	}
+	return m.result.text
+}
+
+func (m *ScriptModule) Style() lipgloss.Style {
+	if m.result.err != nil {
+		return criticalStyle
+	}
+	return classStyle(m.result.class)
+}
+
+func scriptVars(vars exprVars) *starlark.Dict {
+	dict := starlark.NewDict(len(vars))
+	for key, value := range vars {
+		var v starlark.Value
+		switch value := value.(type) {
+		case bool:
+			v = starlark.Bool(value)
+		case int:
+			v = starlark.MakeInt(value)
+		case float64:
+			v = starlark.Float(value)
+		case string:
+			v = starlark.String(value)
+		default:
+			v = starlark.String(fmt.Sprint(value))
+		}
+		dict.SetKey(starlark.String(key), v)
+	}
+	dict.Freeze()
+	return dict
+}
+
+func toScriptResult(value starlark.Value) scriptResult {
+	switch value := value.(type) {
+	case starlark.NoneType:
+		return scriptResult{}
+	case starlark.String:
+		return scriptResult{text: string(value), visible: true}
+	case *starlark.Dict:
+		result := scriptResult{visible: true}
+		if v, ok, _ := value.Get(starlark.String("text")); ok {
+			result.text = scriptString(v)
+		}
+		if v, ok, _ := value.Get(starlark.String("class")); ok {
+			result.class = scriptString(v)
+		}
+		if v, ok, _ := value.Get(starlark.String("visible")); ok {
+			result.visible = bool(v.Truth())
+		}
+		return result
+	}
+	return scriptResult{text: value.String(), visible: true}
+}
+
+func scriptString(v starlark.Value) string {
+	if s, ok := starlark.AsString(v); ok {
+		return s
+	}
+	return v.String()
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		return filepath.Join(os.Getenv("HOME"), rest)
+	}
+	return path
+}
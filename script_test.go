@@ -0,0 +1,21 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScriptErrorRender(t *testing.T) {
+	m := newScriptModule(&moduleContext{}, ScriptConfig{Name: "weather"})
+	m.ready = true
+	m.result = scriptResult{err: errors.New("step limit exceeded")}
+	if got, want := m.Render(), "󰀦 weather"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+
+	textLabels = true
+	defer func() { textLabels = false }()
+	if got, want := m.Render(), "error weather"; got != want {
+		t.Errorf("Render() with text labels = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Secrets such as API keys never live in config.json. Config refers to
+// them by reference instead:
+//   "env:OWM_API_KEY"          an environment variable
+//   "cmd:pass show weather"    the first line a command prints
+//   "weather"                  a key in ~/.config/tui-statusbar/secrets.json,
+//                              which must not be readable by group or others
+
+const secretCommandTimeout = 10 * time.Second
+
+var (
+	secretsMu    sync.Mutex
+	secretsCache = make(map[string]string)
+)
+
+func secretsPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "tui-statusbar", "secrets.json")
+}
+
+func resolveSecret(ref string) (string, error) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if value, ok := secretsCache[ref]; ok {
+		return value, nil
+	}
+
+	var value string
+	var err error
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		name := strings.TrimPrefix(ref, "env:")
+		var ok bool
+		if value, ok = os.LookupEnv(name); !ok {
+			err = fmt.Errorf("environment variable %s is not set", name)
+		}
+	case strings.HasPrefix(ref, "cmd:"):
+		value, err = secretFromCommand(strings.TrimPrefix(ref, "cmd:"))
+	default:
+		value, err = secretFromFile(ref)
+	}
+	if err != nil {
+		return "", fmt.Errorf("secret %q: %w", ref, err)
+	}
+	secretsCache[ref] = value
+	return value, nil
+}
+
+func secretFromCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretCommandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "sh", "-c", command).Output()
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := bytes.Cut(out, []byte("\n"))
+	return string(bytes.TrimSpace(line)), nil
+}
+
+func secretFromFile(key string) (string, error) {
+	path := secretsPath()
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("%s is accessible by other users, chmod 600 it", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var secrets map[string]string
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return "", fmt.Errorf("%s: %v", path, err)
+	}
+	value, ok := secrets[key]
+	if !ok {
+		return "", fmt.Errorf("not in %s", path)
+	}
+	return value, nil
+}
+
+// secretEnv resolves a name -> reference map into KEY=value pairs for a
+// child process.
+func secretEnv(refs map[string]string) ([]string, error) {
+	env := make([]string, 0, len(refs))
+	for name, ref := range refs {
+		value, err := resolveSecret(ref)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, name+"="+value)
+	}
+	return env, nil
+}
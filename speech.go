@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SpeechConfig enables a plain-language summary of the bar, e.g.
+// "Battery 85 percent charging, workspace 3, 14:02", written to Path every
+// Interval seconds when it changes. Path may be a regular file or a FIFO
+// read by a speech tool; a FIFO nobody is reading is skipped.
+type SpeechConfig struct {
+	Path     string `json:"path"`
+	Interval int    `json:"interval"`
+}
+
+// describer is implemented by modules that can put their state into words.
+type describer interface {
+	Describe() string
+}
+
+func (m model) describe() string {
+	vars := m.ruleVars()
+	var parts []string
+	for _, section := range m.sections {
+		for _, id := range section {
+			mod, ok := m.moduleByID[id]
+			if !ok || !m.moduleVisible(id, vars) {
+				continue
+			}
+			d, ok := mod.(describer)
+			if !ok {
+				continue
+			}
+			var text string
+			if m.faults.guard(id, "describe", func() { text = d.Describe() }) && text != "" {
+				parts = append(parts, text)
+			}
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (m model) speak(now time.Time) (model, tea.Cmd) {
+	config := m.config.Speech
+	if config.Path == "" || now.Sub(m.lastSpoken) < time.Duration(max(config.Interval, 1))*time.Second {
+		return m, nil
+	}
+	m.lastSpoken = now
+
+	line := m.describe()
+	if line == m.spoken {
+		return m, nil
+	}
+	m.spoken = line
+	path := expandHome(config.Path)
+	return m, func() tea.Msg {
+		if err := writeSpeech(path, line+"\n"); err != nil {
+			log.Printf("speech output %s: %v", path, err)
+		}
+		return nil
+	}
+}
+
+func writeSpeech(path, line string) error {
+	info, err := os.Stat(path)
+	if err == nil && info.Mode()&os.ModeNamedPipe != 0 {
+		f, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+		if errors.Is(err, syscall.ENXIO) {
+			return nil // no reader
+		}
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = f.WriteString(line)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(line), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
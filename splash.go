@@ -0,0 +1,31 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+type spinnerMsg struct{}
+
+func spinnerCmd() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg {
+		return spinnerMsg{}
+	})
+}
+
+func (m model) allReady() bool {
+	for _, mod := range m.modules {
+		if !m.faults.failed(mod.Name()) && !moduleReady(mod) {
+			return false
+		}
+	}
+	return true
+}
+
+func placeholder(glyph string) string {
+	frame := time.Now().UnixMilli() / 100
+	return icon(glyph) + " " + spinnerFrames[frame%int64(len(spinnerFrames))]
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runtime state kept across restarts, stored in $XDG_STATE_HOME/tui-bar/state.json
+
+type runtimeState struct {
+	Hidden  bool                       `json:"hidden"`
+	Focus   bool                       `json:"focus,omitempty"`
+	Modules map[string]json.RawMessage `json:"modules,omitempty"`
+}
+
+type statefulModule interface {
+	SaveState() any
+	RestoreState(data json.RawMessage)
+}
+
+func stateDir() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "tui-bar")
+}
+
+func statePath() string {
+	if dir := stateDir(); dir != "" {
+		return filepath.Join(dir, "state.json")
+	}
+	return ""
+}
+
+// logToFile sends the log package, which recovered panics and module
+// errors go through, to $XDG_STATE_HOME/tui-bar/tui-bar.log: anything
+// written to the terminal would garble the bar and vanish with the
+// alternate screen.
+func logToFile() (*os.File, error) {
+	dir := stateDir()
+	if dir == "" {
+		return nil, os.ErrNotExist
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return tea.LogToFile(filepath.Join(dir, "tui-bar.log"), "")
+}
+
+func loadState() runtimeState {
+	var state runtimeState
+	path := statePath()
+	if path == "" {
+		return state
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		log.Printf("ignoring corrupt state file %s: %v", path, err)
+		return runtimeState{}
+	}
+	return state
+}
+
+func saveState(state runtimeState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeState(data)
+}
+
+func writeState(data []byte) error {
+	path := statePath()
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (m model) snapshotState() runtimeState {
+	state := runtimeState{
+		Hidden:  m.hidden,
+		Focus:   m.focus,
+		Modules: make(map[string]json.RawMessage),
+	}
+	for _, mod := range m.modules {
+		sm, ok := mod.(statefulModule)
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(sm.SaveState())
+		if err != nil {
+			log.Printf("%s: failed to save state: %v", mod.Name(), err)
+			continue
+		}
+		state.Modules[mod.Name()] = data
+	}
+	return state
+}
+
+func (m model) restoreState(state runtimeState) model {
+	m.hidden = state.Hidden
+	m.focus = state.Focus
+	for _, mod := range m.modules {
+		sm, ok := mod.(statefulModule)
+		if !ok {
+			continue
+		}
+		if data, ok := state.Modules[mod.Name()]; ok {
+			sm.RestoreState(data)
+		}
+	}
+	return m
+}
+
+// checkpointState writes the state whenever it changes, so a crash (which
+// skips the save on exit) loses at most a tick's worth.
+func (m model) checkpointState() model {
+	data, err := json.MarshalIndent(m.snapshotState(), "", "  ")
+	if err != nil || string(data) == m.savedState {
+		return m
+	}
+	if err := writeState(data); err != nil {
+		log.Printf("failed to checkpoint state: %v", err)
+		return m
+	}
+	m.savedState = string(data)
+	return m
+}
@@ -4,69 +4,238 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// StyleSpec is one step of the style cascade: Inherit names the parent
+// style and the other fields override what it sets. Colors are palette
+// names (primary, surface, text, text_dim, accent, pink, good, warning,
+// critical, border) or literal ANSI/hex colors; Padding takes 1, 2 or 4
+// values like CSS and BorderStyle any type from border.go.
+type StyleSpec struct {
+	Inherit     string `json:"inherit"`
+	BorderStyle string `json:"border_style"`
+	Foreground  string `json:"foreground"`
+	Background  string `json:"background"`
+	BorderColor string `json:"border_color"`
+	Border      *bool  `json:"border"`
+	Padding     []int  `json:"padding"`
+	Bold        *bool  `json:"bold"`
+	Italic      *bool  `json:"italic"`
+	Underline   *bool  `json:"underline"`
+}
+
+// the palette; a theme replaces it and rebuilds the styles
+var palette map[string]lipgloss.TerminalColor
+
+var yes = func(b bool) *bool { return &b }(true)
+
+// baseStyles is the built-in cascade; themes override entries by name
+// and add per-module overrides on top.
+var baseStyles = map[string]StyleSpec{
+	"box":               {Border: yes, BorderColor: "border", Padding: []int{0, 1}, Foreground: "text"},
+	"active":            {Inherit: "box", Foreground: "primary", BorderColor: "primary", Bold: yes},
+	"workspace":         {Inherit: "box", Foreground: "text_dim"},
+	"workspace.active":  {Inherit: "workspace", Foreground: "surface", Background: "border", Bold: yes},
+	"workspace.empty":   {Inherit: "workspace", Foreground: "border"},
+	"workspace.urgent":  {Inherit: "workspace", Foreground: "critical", BorderColor: "critical", Bold: yes},
+	"workspace.special": {Inherit: "workspace", Foreground: "accent", Italic: yes},
+	"cpu":               {Inherit: "box", Foreground: "pink", BorderColor: "accent"},
+	"memory":            {Inherit: "box", Foreground: "pink", BorderColor: "pink"},
+	"disk":              {Inherit: "box"},
+	"battery":           {Inherit: "box"},
+	"battery.charging":  {Inherit: "good"},
+	"battery.low":       {Inherit: "critical"},
+	"network":           {Inherit: "box", Foreground: "accent", BorderColor: "accent"},
+	"clock":             {Inherit: "active"},
+	"layout":            {Inherit: "box", Foreground: "accent"},
+	"window":            {Inherit: "box"},
+	"window.container":  {Foreground: "accent", Bold: yes},
+	"warning":           {Inherit: "box", Foreground: "warning", BorderColor: "warning"},
+	"critical":          {Inherit: "box", Foreground: "critical", BorderColor: "critical"},
+	"good":              {Inherit: "box", Foreground: "good", BorderColor: "good"},
+	"stale":             {Inherit: "box", Foreground: "text_dim"},
+	"dim":               {Inherit: "box", Foreground: "text_dim", BorderColor: "text_dim"},
+	"monitor":           {Inherit: "box", Foreground: "text_dim", BorderColor: "accent"},
+	"monitor.active":    {Inherit: "monitor", Foreground: "accent", Bold: yes},
+	"popup":             {Foreground: "text", Background: "surface"},
+	"popup.selected":    {Inherit: "popup", Foreground: "primary", Bold: yes},
+	"popup.highlight":   {Inherit: "popup", Foreground: "warning"},
+	"tooltip":           {Foreground: "text", Padding: []int{0, 1}},
+	"hidden":            {Foreground: "text_dim"},
+}
+
+var styleTargets = map[string]*lipgloss.Style{
+	"box":               &boxStyle,
+	"active":            &activeBoxStyle,
+	"workspace":         &workspaceStyle,
+	"workspace.active":  &workspaceActiveStyle,
+	"workspace.empty":   &workspaceEmptyStyle,
+	"workspace.urgent":  &workspaceUrgentStyle,
+	"workspace.special": &workspaceSpecialStyle,
+	"cpu":               &cpuStyle,
+	"memory":            &memoryStyle,
+	"disk":              &diskStyle,
+	"battery":           &batteryStyle,
+	"battery.charging":  &batteryChargingStyle,
+	"battery.low":       &batteryLowStyle,
+	"network":           &networkStyle,
+	"clock":             &clockStyle,
+	"layout":            &layoutStyle,
+	"window":            &windowTitleStyle,
+	"window.container":  &containerStyle,
+	"warning":           &warningStyle,
+	"critical":          &criticalStyle,
+	"good":              &goodStyle,
+	"stale":             &staleStyle,
+	"dim":               &dimStyle,
+	"monitor":           &monitorLabelStyle,
+	"monitor.active":    &monitorLabelActiveStyle,
+	"popup":             &popupStyle,
+	"popup.selected":    &popupSelectedStyle,
+	"popup.highlight":   &popupHighlightStyle,
+	"tooltip":           &tooltipStyle,
+	"hidden":            &hiddenLineStyle,
+}
+
 var (
-	primary = lipgloss.Color("4")
-	surface = lipgloss.Color("1")
-	text    = lipgloss.Color("3")
-	textDim = lipgloss.Color("4")
-	purple  = lipgloss.Color("6")
-	pink    = lipgloss.Color("5")
-	green   = lipgloss.Color("7")
-	yellow  = lipgloss.Color("8")
-	red     = lipgloss.Color("9")
-
-	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.AdaptiveColor{
-			Light: "#D7BAFF",
-			Dark:  "#D7BAFF",
-		}).
-		Padding(0, 1).
-		Foreground(text).
-		Background(lipgloss.AdaptiveColor{
-			Light: "transparent",
-			Dark:  "transparent",
-		})
-
-	activeBoxStyle = boxStyle.Copy().
-			BorderForeground(primary).
-			Foreground(primary).
-			Bold(true)
-
-	workspaceStyle = boxStyle.Copy().
-			Foreground(textDim).
-			Padding(0, 1)
-
-	workspaceActiveStyle = workspaceStyle.Copy().
-				Background(lipgloss.Color("#D7BAFF")).
-				Foreground(surface).
-				Bold(true)
-
-	cpuStyle = boxStyle.Copy().
-			Foreground(pink).
-			BorderForeground(purple)
-
-	memoryStyle = boxStyle.Copy().
-			Foreground(pink).
-			BorderForeground(pink)
-
-	diskStyle = boxStyle.Copy().
-			Foreground(text)
-
-	batteryStyle = boxStyle.Copy().
-			Foreground(text)
-
-	batteryChargingStyle = boxStyle.Copy().
-				Foreground(green).
-				BorderForeground(green)
-
-	batteryLowStyle = boxStyle.Copy().
-			Foreground(red).
-			BorderForeground(red)
-
-	networkStyle = boxStyle.Copy().
-			Foreground(purple).
-			BorderForeground(purple)
-
-	clockStyle = activeBoxStyle.Copy()
+	boxStyle                lipgloss.Style
+	activeBoxStyle          lipgloss.Style
+	workspaceStyle          lipgloss.Style
+	workspaceActiveStyle    lipgloss.Style
+	workspaceEmptyStyle     lipgloss.Style
+	workspaceUrgentStyle    lipgloss.Style
+	workspaceSpecialStyle   lipgloss.Style
+	cpuStyle                lipgloss.Style
+	memoryStyle             lipgloss.Style
+	diskStyle               lipgloss.Style
+	batteryStyle            lipgloss.Style
+	batteryChargingStyle    lipgloss.Style
+	batteryLowStyle         lipgloss.Style
+	networkStyle            lipgloss.Style
+	clockStyle              lipgloss.Style
+	layoutStyle             lipgloss.Style
+	windowTitleStyle        lipgloss.Style
+	containerStyle          lipgloss.Style
+	popupStyle              lipgloss.Style
+	tooltipStyle            lipgloss.Style
+	popupSelectedStyle      lipgloss.Style
+	popupHighlightStyle     lipgloss.Style
+	hiddenLineStyle         lipgloss.Style
+	warningStyle            lipgloss.Style
+	staleStyle              lipgloss.Style
+	dimStyle                lipgloss.Style
+	criticalStyle           lipgloss.Style
+	goodStyle               lipgloss.Style
+	monitorLabelStyle       lipgloss.Style
+	monitorLabelActiveStyle lipgloss.Style
+
+	// the current theme's overrides, by style name and by module id
+	themeStyles  map[string]StyleSpec
+	moduleStyles map[string]StyleSpec
 )
+
+func init() {
+	setPalette(builtinThemes["default"])
+	buildStyles()
+}
+
+// buildStyles resolves the cascade for every named style, with padded
+// styles taking the density's padding.
+func buildStyles() {
+	for name, target := range styleTargets {
+		style := resolveStyle(name, 0)
+		if style.GetPaddingLeft() > 0 || style.GetPaddingRight() > 0 {
+			style = style.PaddingLeft(density.padding).PaddingRight(density.padding)
+		}
+		*target = style
+	}
+}
+
+func resolveStyle(name string, depth int) lipgloss.Style {
+	base, builtin := baseStyles[name]
+	override, themed := themeStyles[name]
+	// an inheritance loop in a theme ends at a plain style
+	if !builtin && !themed || depth > 8 {
+		return lipgloss.NewStyle()
+	}
+
+	parent := base.Inherit
+	if override.Inherit != "" {
+		parent = override.Inherit
+	}
+	style := lipgloss.NewStyle()
+	if parent != "" {
+		style = resolveStyle(parent, depth+1)
+	}
+	return override.apply(base.apply(style))
+}
+
+func (s StyleSpec) apply(style lipgloss.Style) lipgloss.Style {
+	if s.Foreground != "" {
+		style = style.Foreground(paletteColor(s.Foreground))
+	}
+	if s.Background != "" {
+		style = style.Background(paletteColor(s.Background))
+	}
+	if s.BorderColor != "" {
+		style = style.BorderForeground(paletteColor(s.BorderColor))
+	}
+	if s.Border != nil {
+		if *s.Border {
+			style = style.Border(lipgloss.NormalBorder())
+		} else {
+			style = style.Border(lipgloss.Border{}, false)
+		}
+	}
+	if s.BorderStyle != "" {
+		style = withBorder(style, s.BorderStyle)
+	}
+	if len(s.Padding) > 0 {
+		style = style.Padding(s.Padding...)
+	}
+	if s.Bold != nil {
+		style = style.Bold(*s.Bold)
+	}
+	if s.Italic != nil {
+		style = style.Italic(*s.Italic)
+	}
+	if s.Underline != nil {
+		style = style.Underline(*s.Underline)
+	}
+	return style
+}
+
+func paletteColor(name string) lipgloss.TerminalColor {
+	if c, ok := palette[name]; ok {
+		return c
+	}
+	return lipgloss.Color(name)
+}
+
+// moduleStyle applies the theme's per-module override to the style a
+// module picked (an override with Inherit replaces it with that style),
+// then the configured border.
+func moduleStyle(id string, style lipgloss.Style) lipgloss.Style {
+	if spec, ok := moduleStyles[id]; ok {
+		if spec.Inherit != "" {
+			style = resolveStyle(spec.Inherit, 0)
+		}
+		style = spec.apply(style)
+	}
+	if kind, ok := moduleBorders[id]; ok {
+		style = withBorder(style, kind)
+	}
+	return style
+}
+
+func classStyle(class string) lipgloss.Style {
+	switch class {
+	case "warning":
+		return warningStyle
+	case "critical", "urgent":
+		return criticalStyle
+	case "good", "success":
+		return goodStyle
+	case "accent":
+		return activeBoxStyle
+	}
+	return boxStyle
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// sunTimes is the sunrise equation for day at latitude/longitude in
+// degrees. polar is +1 for a day the sun never sets, -1 for one it never
+// rises, and 0 when rise and set are valid.
+func sunTimes(day time.Time, lat, lon float64) (rise, set time.Time, polar int) {
+	const rad = math.Pi / 180
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	julian := float64(midnight.Unix())/86400 + 2440587.5
+
+	n := math.Ceil(julian - 2451545.0 + 0.0008)
+	mean := n - lon/360
+	anomaly := math.Mod(357.5291+0.98560028*mean, 360)
+	center := 1.9148*math.Sin(anomaly*rad) + 0.02*math.Sin(2*anomaly*rad) + 0.0003*math.Sin(3*anomaly*rad)
+	ecliptic := math.Mod(anomaly+center+180+102.9372, 360)
+	transit := 2451545.0 + mean + 0.0053*math.Sin(anomaly*rad) - 0.0069*math.Sin(2*ecliptic*rad)
+
+	declination := math.Asin(math.Sin(ecliptic*rad) * math.Sin(23.4397*rad))
+	cosHour := (math.Sin(-0.833*rad) - math.Sin(lat*rad)*math.Sin(declination)) / (math.Cos(lat*rad) * math.Cos(declination))
+	switch {
+	case cosHour < -1:
+		return time.Time{}, time.Time{}, 1
+	case cosHour > 1:
+		return time.Time{}, time.Time{}, -1
+	}
+	hour := math.Acos(cosHour) / rad
+
+	fromJulian := func(j float64) time.Time {
+		return time.Unix(int64((j-2440587.5)*86400), 0).In(day.Location())
+	}
+	return fromJulian(transit - hour/360), fromJulian(transit + hour/360), 0
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	superviseMinBackoff = time.Second
+	superviseMaxBackoff = 30 * time.Second
+	superviseStableRun  = time.Minute
+)
+
+// runSupervisor re-runs the bar as a child process and restarts it with
+// exponential backoff whenever it exits abnormally. The child checkpoints
+// its runtime state, so a restart picks up where the crash left off.
+func runSupervisor(args []string) int {
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Printf("Err: cannot locate executable: %v\n", err)
+		return 1
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	backoff := superviseMinBackoff
+	for {
+		cmd := exec.Command(exe, args...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		cmd.Env = append(os.Environ(), "TUI_BAR_SUPERVISED=1")
+
+		start := time.Now()
+		if err := cmd.Start(); err != nil {
+			fmt.Printf("Err: failed to start bar: %v\n", err)
+			return 1
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- cmd.Wait() }()
+
+		select {
+		case sig := <-signals:
+			cmd.Process.Signal(sig)
+			<-done
+			return 0
+		case err = <-done:
+		}
+		if err == nil {
+			return 0
+		}
+
+		if time.Since(start) > superviseStableRun {
+			backoff = superviseMinBackoff
+		}
+		fmt.Fprintf(os.Stderr, "tui-bar exited: %v, restarting in %s\n", err, backoff)
+
+		select {
+		case <-signals:
+			return 0
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, superviseMaxBackoff)
+	}
+}
+
+// withoutFlag drops a boolean flag in any of its forms: -name, --name,
+// -name=value and --name=value.
+func withoutFlag(args []string, name string) []string {
+	var out []string
+	for _, arg := range args {
+		flag, _, _ := strings.Cut(arg, "=")
+		if strings.HasPrefix(arg, "-") && strings.TrimLeft(flag, "-") == name {
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
@@ -1,65 +1,267 @@
 package main
 
 import (
+	"fmt"
 	"math"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/distatus/battery"
+	"github.com/godbus/dbus/v5"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
 )
 
-func fetchSystemStats() (float64, float64, float64) {
+func fetchCPUUsage() (float64, error) {
 	cpuPercent, err := cpu.Percent(0, false)
-	cpuUsage := 0.0
-	if err == nil && len(cpuPercent) > 0 {
-		cpuUsage = math.Round(cpuPercent[0]*10) / 10
+	if err != nil {
+		return 0, err
 	}
+	if len(cpuPercent) == 0 {
+		return 0, fmt.Errorf("no cpu stats")
+	}
+	return math.Round(cpuPercent[0]*10) / 10, nil
+}
+
+func fetchMemoryUsage() (float64, error) {
+	memInfo, err := mem.VirtualMemory()
+	if err != nil {
+		return 0, err
+	}
+	return math.Round(memInfo.UsedPercent*10) / 10, nil
+}
 
+func fetchMemoryUsed() (float64, error) {
 	memInfo, err := mem.VirtualMemory()
-	memUsage := 0.0
-	if err == nil {
-		memUsage = math.Round(memInfo.UsedPercent*10) / 10
+	if err != nil {
+		return 0, err
 	}
+	return float64(memInfo.Used), nil
+}
 
+func fetchDiskUsage() (float64, error) {
 	diskInfo, err := disk.Usage("/")
-	diskUsage := 0.0
-	if err == nil {
-		diskUsage = math.Round(diskInfo.UsedPercent*10) / 10
+	if err != nil {
+		return 0, err
 	}
-	return cpuUsage, memUsage, diskUsage
+	return math.Round(diskInfo.UsedPercent*10) / 10, nil
+}
+
+type batteryStats struct {
+	level   int
+	state   string
+	present bool
+	health  float64
+	limit   int // charge cap set in firmware, 0 if none
 }
 
-func fetchBatteryStats() (int, string) {
+func fetchBatteryStats() batteryStats {
 	batteries, err := battery.GetAll()
-	if err != nil || len(batteries) == 0 {
-		return 0, "unknown"
+	if len(batteries) == 0 {
+		if err != nil {
+			return batteryStats{state: "unknown"}
+		}
+		return batteryStats{state: "none"}
 	}
 
 	bat := batteries[0]
-	level := int(bat.Current / bat.Full * 100)
-
-	stateStr := bat.State.String()
-	state := "discharging"
+	percent, ok := readBatteryPercent()
+	if !ok {
+		percent = batteryRatio(bat.Current, bat.Full, bat.Design)
+	}
+	level := clampPercent(percent)
 
-	// Fix: Use the State field correctly
-	switch stateStr {
-	case "Charging":
+	state := "unknown"
+	switch bat.State.Raw {
+	case battery.Charging:
 		state = "charging"
-	case "Full":
+	case battery.Full:
 		state = "full"
-	case "Discharging":
+	case battery.Discharging:
 		state = "discharging"
+	case battery.Empty:
+		state = "empty"
+	case battery.Idle:
+		state = "idle"
 	default:
-		state = "unknown"
+		// firmwares report Unknown when plugged in but not charging
+		if level >= 95 {
+			state = "full"
+		}
+	}
+
+	health := 0.0
+	if bat.Design > 0 && bat.Full > 0 {
+		health = math.Round(math.Min(bat.Full/bat.Design*100, 100)*10) / 10
+	}
+
+	return batteryStats{
+		level:   level,
+		state:   state,
+		present: true,
+		health:  health,
+		limit:   readChargeLimit(),
+	}
+}
+
+func batteryRatio(current, full, design float64) float64 {
+	if full <= 0 || math.IsNaN(full) {
+		full = design
+	}
+	if full <= 0 || math.IsNaN(current) {
+		return math.NaN()
+	}
+	return current / full * 100
+}
+
+func clampPercent(percent float64) int {
+	if math.IsNaN(percent) || math.IsInf(percent, 0) {
+		return 0
+	}
+	return int(math.Round(math.Max(0, math.Min(100, percent))))
+}
+
+type networkInfo struct {
+	name     string
+	state    string // connected or disconnected
+	wireless bool
+	ssid     string
+}
+
+// fetchNetworkInfo finds the interface carrying the default route, or
+// failing that the first one that's up, and for WiFi (when asked; it takes
+// a few D-Bus calls) the network it's on.
+func fetchNetworkInfo(withSSID bool) networkInfo {
+	name := defaultRouteInterface()
+	if name == "" {
+		entries, _ := os.ReadDir("/sys/class/net")
+		for _, e := range entries {
+			if e.Name() != "lo" && readSysfsString("/sys/class/net/"+e.Name(), "operstate") == "up" {
+				name = e.Name()
+				break
+			}
+		}
+	}
+	if name == "" {
+		return networkInfo{state: "disconnected"}
+	}
+
+	info := networkInfo{name: name, state: "connected"}
+	if _, err := os.Stat("/sys/class/net/" + name + "/wireless"); err == nil {
+		info.wireless = true
+		if withSSID {
+			info.ssid = wifiSSID(name)
+		}
 	}
-	return level, state
+	return info
 }
 
-func fetchNetworkInfo() (string, string) {
-	return "wlan0", "connected"
+// defaultRouteInterface reads /proc/net/route for the IPv4 default route
+// with the lowest metric:
+//
+//	Iface  Destination  Gateway  Flags  RefCnt  Use  Metric  Mask ...
+//	wlan0  00000000     0102A8C0 0003   0       0    600     00000000
+func defaultRouteInterface() string {
+	data, err := os.ReadFile("/proc/net/route")
+	if err != nil {
+		return ""
+	}
+	best, bestMetric := "", math.MaxInt
+	for _, line := range strings.Split(string(data), "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		if metric, err := strconv.Atoi(fields[6]); err == nil && metric < bestMetric {
+			best, bestMetric = fields[0], metric
+		}
+	}
+	return best
+}
+
+// wifiSSID asks NetworkManager which network the interface is on, then
+// falls back to iw for setups without it.
+func wifiSSID(name string) string {
+	if active, err := nmActiveConnections(); err == nil {
+		for _, c := range active {
+			if c.kind != "802-11-wireless" || !slices.ContainsFunc(c.devices, func(d dbus.ObjectPath) bool { return nmDeviceInterface(d) == name }) {
+				continue
+			}
+			if settings, err := nmConnectionSettings(c.settings); err == nil {
+				if ssid, ok := settings["802-11-wireless"]["ssid"].Value().([]byte); ok {
+					return string(ssid)
+				}
+			}
+			return c.id
+		}
+	}
+
+	out, err := exec.Command("iw", "dev", name, "link").Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if ssid, ok := strings.CutPrefix(strings.TrimSpace(line), "SSID: "); ok {
+			return ssid
+		}
+	}
+	return ""
 }
 
 func fetchHyprlandInfo() (int, string) {
 	return 1, "nvim"
 }
+
+type netCounters struct {
+	rx, tx uint64
+}
+
+// fetchNetCounters reads bytes received and sent since boot on one
+// interface.
+func fetchNetCounters(name string) (netCounters, error) {
+	stats, err := net.IOCounters(true)
+	if err != nil {
+		return netCounters{}, err
+	}
+	for _, s := range stats {
+		if s.Name == name {
+			return netCounters{rx: s.BytesRecv, tx: s.BytesSent}, nil
+		}
+	}
+	return netCounters{}, fmt.Errorf("no counters for interface %q", name)
+}
+
+// readWifiSignal returns the signal level in dBm of the named wireless
+// interface, or the first one, from /proc/net/wireless:
+//
+//	wlan0: 0000   54.  -56.  -256        0      0      0      0     96        0
+func readWifiSignal(name string) (int, bool) {
+	data, err := os.ReadFile("/proc/net/wireless")
+	if err != nil {
+		return 0, false
+	}
+	signal, found := 0, false
+	for _, line := range strings.Split(string(data), "\n") {
+		iface, rest, ok := strings.Cut(strings.TrimSpace(line), ":")
+		fields := strings.Fields(rest)
+		if !ok || len(fields) < 3 {
+			continue
+		}
+		level, err := strconv.ParseFloat(strings.TrimSuffix(fields[2], "."), 64)
+		if err != nil {
+			continue
+		}
+		if iface == name || !found {
+			signal, found = int(level), true
+		}
+		if iface == name {
+			break
+		}
+	}
+	return signal, found
+}
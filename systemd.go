@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// sd_notify protocol: datagrams to $NOTIFY_SOCKET, no libsystemd needed
+
+func sdNotify(state string) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return
+	}
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.Write([]byte(state))
+}
+
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
+
+// pingWatchdog runs off the tick, so a wedged event loop stops the pings
+// and lets systemd restart the bar.
+func (m model) pingWatchdog(now time.Time) model {
+	interval := sdWatchdogInterval()
+	if interval == 0 || now.Sub(m.lastWatchdog) < interval {
+		return m
+	}
+	sdNotify("WATCHDOG=1")
+	m.lastWatchdog = now
+	return m
+}
+
+// terminals the unit can launch the bar in, with a window class/app-id of
+// "tui-bar" so compositor rules can place it
+var serviceTerminals = []struct {
+	name string
+	args []string
+}{
+	{"foot", []string{"--app-id", "tui-bar"}},
+	{"kitty", []string{"--class", "tui-bar"}},
+	{"alacritty", []string{"--class", "tui-bar", "-e"}},
+	{"wezterm", []string{"start", "--class", "tui-bar", "--"}},
+}
+
+var serviceUnit = template.Must(template.New("unit").Parse(`[Unit]
+Description=tui-bar status bar
+PartOf=graphical-session.target
+After=graphical-session.target
+
+[Service]
+Type=notify
+NotifyAccess=all
+ExecStart={{.ExecStart}}
+Restart=on-failure
+RestartSec=2
+WatchdogSec=10
+
+[Install]
+WantedBy=graphical-session.target
+`))
+
+// terminalCommand returns the argv that runs command inside terminal, or
+// inside the first supported terminal found when none is named.
+func terminalCommand(terminal string, command ...string) ([]string, error) {
+	if terminal == "" {
+		terminal = os.Getenv("TERMINAL")
+	}
+	for _, t := range serviceTerminals {
+		if terminal != "" && filepath.Base(terminal) != t.name {
+			continue
+		}
+		path, err := exec.LookPath(t.name)
+		if err != nil {
+			if terminal != "" {
+				return nil, fmt.Errorf("terminal %s not found", t.name)
+			}
+			continue
+		}
+		argv := append([]string{path}, t.args...)
+		return append(argv, command...), nil
+	}
+	if terminal != "" {
+		return nil, fmt.Errorf("unsupported terminal %q", terminal)
+	}
+	return nil, fmt.Errorf("no supported terminal found (tried foot, kitty, alacritty, wezterm)")
+}
+
+func runInstallService(args []string) int {
+	var terminal string
+	if len(args) > 0 {
+		terminal = args[0]
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Printf("Err: cannot locate executable: %v\n", err)
+		return 1
+	}
+	argv, err := terminalCommand(terminal, exe)
+	if err != nil {
+		fmt.Printf("Err: %v\n", err)
+		return 1
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		fmt.Printf("Err: %v\n", err)
+		return 1
+	}
+	path := filepath.Join(configDir, "systemd", "user", "tui-bar.service")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		fmt.Printf("Err: %v\n", err)
+		return 1
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Printf("Err: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+	if err := serviceUnit.Execute(f, struct{ ExecStart string }{systemdCommandLine(argv)}); err != nil {
+		fmt.Printf("Err: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("wrote %s\n", path)
+	fmt.Println("enable it with: systemctl --user daemon-reload && systemctl --user enable --now tui-bar.service")
+	return 0
+}
+
+// systemdCommandLine quotes argv for ExecStart: words with spaces, quotes or
+// backslashes go in double quotes with C escapes, and % and $ are doubled so
+// systemd doesn't expand them as specifiers or variables.
+func systemdCommandLine(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		arg = strings.NewReplacer("%", "%%", "$", "$$").Replace(arg)
+		if arg != "" && arg != ";" && !strings.ContainsAny(arg, " \t\n\"'\\") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\t", `\t`, "\n", `\n`).Replace(arg) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
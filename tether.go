@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// a connection is shared when NetworkManager runs it with ipv4.method
+// "shared": a wifi hotspot (802-11-wireless.mode "ap") or a wired/USB
+// tether handing out addresses to the other side
+
+type sharedConnection struct {
+	active  dbus.ObjectPath
+	name    string
+	hotspot bool
+	iface   string
+	clients int
+}
+
+func fetchSharedConnections() ([]sharedConnection, error) {
+	active, err := nmActiveConnections()
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []sharedConnection
+	for _, c := range active {
+		settings, err := nmConnectionSettings(c.settings)
+		if err != nil || nmSetting(settings, "ipv4", "method") != "shared" {
+			continue
+		}
+		s := sharedConnection{
+			active:  c.path,
+			name:    c.id,
+			hotspot: nmSetting(settings, "802-11-wireless", "mode") == "ap",
+		}
+		if len(c.devices) > 0 {
+			s.iface = nmDeviceInterface(c.devices[0])
+			s.clients = arpClients(s.iface)
+		}
+		shared = append(shared, s)
+	}
+	return shared, nil
+}
+
+// arpClients counts resolved neighbours on iface, which for a shared
+// connection are the devices using it.
+func arpClients(iface string) int {
+	f, err := os.Open("/proc/net/arp")
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	count := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// IP address  HW type  Flags  HW address  Mask  Device
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 6 && fields[5] == iface && fields[2] == "0x2" {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rivo/uniseg"
+)
+
+// width math for the bar: everything is measured in terminal cells by
+// grapheme, so wide CJK and emoji count two, combining marks none and
+// escape sequences nothing
+
+func textWidth(s string) int {
+	return ansi.StringWidth(s)
+}
+
+// truncate shortens text to at most n cells, ending in "…", and lays any
+// right-to-left part out in visual order. Cutting happens first so it's
+// always the logical end of the text that goes.
+func truncate(text string, n int) string {
+	return visualOrder(ansi.Truncate(sanitizeText(text), n, "…"))
+}
+
+// marquee shows n cells of text that doesn't fit, starting offset
+// graphemes in and wrapping around after a gap, so a long title can
+// scroll through a fixed slot. Text that fits is returned as it is.
+func marquee(text string, n, offset int) string {
+	text = sanitizeText(text)
+	if textWidth(text) <= n {
+		return visualOrder(text)
+	}
+	var clusters []string
+	g := uniseg.NewGraphemes(text + "   ")
+	for g.Next() {
+		clusters = append(clusters, g.Str())
+	}
+	offset %= len(clusters)
+	rotated := strings.Join(slices.Concat(clusters[offset:], clusters[:offset]), "")
+	return padRight(visualOrder(ansi.Truncate(rotated, n, "")), n)
+}
+
+// padRight pads text with spaces to n cells.
+func padRight(text string, n int) string {
+	return text + strings.Repeat(" ", max(n-textWidth(text), 0))
+}
+
+// sanitizeText turns control characters (tabs, newlines, stray escapes
+// in window titles) into spaces so they can't move the cursor mid-bar.
+func sanitizeText(s string) string {
+	if !strings.ContainsFunc(s, unicode.IsControl) {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a palette of ANSI color numbers or hex colors, plus overrides
+// of the named styles (see baseStyles) and of individual modules' styles.
+// Palette entries left empty keep the default theme's.
+type Theme struct {
+	Primary  string `json:"primary"`
+	Surface  string `json:"surface"`
+	Text     string `json:"text"`
+	TextDim  string `json:"text_dim"`
+	Accent   string `json:"accent"`
+	Pink     string `json:"pink"`
+	Good     string `json:"good"`
+	Warning  string `json:"warning"`
+	Critical string `json:"critical"`
+	Border   string `json:"border"`
+
+	Styles  map[string]StyleSpec `json:"styles"`
+	Modules map[string]StyleSpec `json:"modules"`
+}
+
+// ThemeConfig picks the theme. Mode "" uses Name; "schedule" uses Light
+// from LightFrom until LightUntil and Dark otherwise; "sun" uses Light
+// between sunrise and sunset at Latitude/Longitude; "system" follows the
+// desktop's dark/light preference, using Name when it has none. `tui-bar msg theme
+// <name>` overrides the mode until `theme auto`.
+type ThemeConfig struct {
+	Name       string           `json:"name"`
+	Mode       string           `json:"mode"`
+	Light      string           `json:"light"`
+	Dark       string           `json:"dark"`
+	LightFrom  string           `json:"light_from"`
+	LightUntil string           `json:"light_until"`
+	Latitude   float64          `json:"latitude"`
+	Longitude  float64          `json:"longitude"`
+	Themes     map[string]Theme `json:"themes"`
+}
+
+var builtinThemes = map[string]Theme{
+	// the terminal's own palette
+	"default": {
+		Primary: "4", Surface: "1", Text: "3", TextDim: "4", Accent: "6",
+		Pink: "5", Good: "7", Warning: "8", Critical: "9", Border: "#D7BAFF",
+	},
+	"dark": {
+		Primary: "#CBA6F7", Surface: "#1E1E2E", Text: "#CDD6F4", TextDim: "#7F849C", Accent: "#B4BEFE",
+		Pink: "#F5C2E7", Good: "#A6E3A1", Warning: "#F9E2AF", Critical: "#F38BA8", Border: "#CBA6F7",
+	},
+	"light": {
+		Primary: "#8839EF", Surface: "#EFF1F5", Text: "#4C4F69", TextDim: "#8C8FA1", Accent: "#7287FD",
+		Pink: "#EA76CB", Good: "#40A02B", Warning: "#DF8E1D", Critical: "#D20F39", Border: "#8839EF",
+	},
+}
+
+func (c ThemeConfig) lookup(name string) (Theme, bool) {
+	t, ok := c.Themes[name]
+	if !ok {
+		t, ok = loadThemeFile(name)
+	}
+	if !ok {
+		t, ok = builtinThemes[name]
+	}
+	if !ok {
+		return Theme{}, false
+	}
+	base := builtinThemes["default"]
+	for _, f := range []struct{ field, fallback *string }{
+		{&t.Primary, &base.Primary}, {&t.Surface, &base.Surface}, {&t.Text, &base.Text},
+		{&t.TextDim, &base.TextDim}, {&t.Accent, &base.Accent}, {&t.Pink, &base.Pink},
+		{&t.Good, &base.Good}, {&t.Warning, &base.Warning}, {&t.Critical, &base.Critical},
+		{&t.Border, &base.Border},
+	} {
+		if *f.field == "" {
+			*f.field = *f.fallback
+		}
+	}
+	return t, true
+}
+
+// theme files live in ~/.config/tui-statusbar/themes/<name>.json
+func themeDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "tui-statusbar", "themes")
+}
+
+func loadThemeFile(name string) (Theme, bool) {
+	var t Theme
+	if name == "" || strings.ContainsAny(name, "/.") {
+		return t, false
+	}
+	data, err := os.ReadFile(filepath.Join(themeDir(), name+".json"))
+	if err != nil {
+		return t, false
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		log.Printf("theme %s: %v", name, err)
+		return t, false
+	}
+	return t, true
+}
+
+func (c ThemeConfig) names() []string {
+	names := slices.Collect(maps.Keys(builtinThemes))
+	names = append(names, slices.Collect(maps.Keys(c.Themes))...)
+	files, _ := filepath.Glob(filepath.Join(themeDir(), "*.json"))
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(filepath.Base(f), ".json"))
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// applyTheme swaps the palette and rebuilds every style, so the next
+// frame is drawn entirely in the new theme.
+func applyTheme(t Theme) {
+	setPalette(t)
+	themeStyles, moduleStyles = t.Styles, t.Modules
+	buildStyles()
+	applyAccessibility(accessibility)
+}
+
+func setPalette(t Theme) {
+	palette = map[string]lipgloss.TerminalColor{
+		"primary":  lipgloss.Color(t.Primary),
+		"surface":  lipgloss.Color(t.Surface),
+		"text":     lipgloss.Color(t.Text),
+		"text_dim": lipgloss.Color(t.TextDim),
+		"accent":   lipgloss.Color(t.Accent),
+		"pink":     lipgloss.Color(t.Pink),
+		"good":     lipgloss.Color(t.Good),
+		"warning":  lipgloss.Color(t.Warning),
+		"critical": lipgloss.Color(t.Critical),
+		"border":   lipgloss.Color(t.Border),
+	}
+}
+
+// scheduled is the theme the mode asks for at now, given the desktop's
+// color-scheme preference.
+func (c ThemeConfig) scheduled(now time.Time, scheme uint32) string {
+	var light bool
+	switch c.Mode {
+	case "schedule":
+		from, err1 := time.Parse("15:04", c.LightFrom)
+		until, err2 := time.Parse("15:04", c.LightUntil)
+		if err1 != nil || err2 != nil {
+			return c.Name
+		}
+		minute := now.Hour()*60 + now.Minute()
+		start, end := from.Hour()*60+from.Minute(), until.Hour()*60+until.Minute()
+		if start <= end {
+			light = minute >= start && minute < end
+		} else {
+			light = minute >= start || minute < end
+		}
+	case "sun":
+		rise, set, polar := sunTimes(now, c.Latitude, c.Longitude)
+		if polar != 0 {
+			light = polar > 0
+		} else {
+			light = !now.Before(rise) && now.Before(set)
+		}
+	case "system":
+		switch scheme {
+		case colorSchemeDark:
+			return c.Dark
+		case colorSchemeLight:
+			return c.Light
+		}
+		return c.Name
+	default:
+		return c.Name
+	}
+	if light {
+		return c.Light
+	}
+	return c.Dark
+}
+
+// a previewed theme reverts unless confirmed within this long
+const themePreviewTimeout = 10 * time.Second
+
+type themePreview struct {
+	until    time.Time
+	previous string // the override to go back to
+}
+
+// updateTheme applies the override or scheduled theme when it changes.
+func (m model) updateTheme(now time.Time) model {
+	if m.preview != nil && now.After(m.preview.until) {
+		m.themeOverride = m.preview.previous
+		m.preview = nil
+	}
+	name := m.themeOverride
+	if name == "" {
+		name = m.config.Theme.scheduled(now, m.appearance.Scheme())
+	}
+	if name == m.theme {
+		return m
+	}
+	t, ok := m.config.Theme.lookup(name)
+	if !ok {
+		log.Printf("unknown theme %q", name)
+		m.theme = name
+		return m
+	}
+	applyTheme(t)
+	m.theme = name
+	return m
+}
+
+func (m model) themeReply(args []string) (model, string) {
+	if len(args) == 0 {
+		return m, m.theme
+	}
+	name := args[0]
+	switch {
+	case name == "list":
+		return m, strings.Join(m.config.Theme.names(), " ")
+	case name == "confirm":
+		if m.preview == nil {
+			return m, "error: no theme preview"
+		}
+		m.preview = nil
+		return m, "ok"
+	case name == "revert":
+		if m.preview == nil {
+			return m, "error: no theme preview"
+		}
+		m.themeOverride, m.preview = m.preview.previous, nil
+	case name == "preview":
+		if len(args) < 2 {
+			return m, "error: usage: theme preview <name>"
+		}
+		if _, ok := m.config.Theme.lookup(args[1]); !ok {
+			return m, fmt.Sprintf("error: unknown theme %q", args[1])
+		}
+		// previewing again keeps the original to revert to
+		previous := m.themeOverride
+		if m.preview != nil {
+			previous = m.preview.previous
+		}
+		m.preview = &themePreview{until: time.Now().Add(themePreviewTimeout), previous: previous}
+		m.themeOverride = args[1]
+	case name == "auto":
+		m.themeOverride, m.preview = "", nil
+	default:
+		if _, ok := m.config.Theme.lookup(name); !ok {
+			return m, fmt.Sprintf("error: unknown theme %q", name)
+		}
+		m.themeOverride, m.preview = name, nil
+	}
+	m = m.updateTheme(time.Now())
+	return m, "ok"
+}
+
+// runThemeCLI is `tui-bar theme <list|name|auto|preview name|confirm|revert>`
+// against the running bar. preview asks whether to keep the theme and
+// confirms it on "y"; otherwise the bar reverts by itself.
+func runThemeCLI(args []string) int {
+	if len(args) == 0 {
+		fmt.Println("usage: tui-bar theme <list|auto|NAME|preview NAME|confirm|revert>")
+		return 2
+	}
+	if code := runMsgCommand(append([]string{"theme"}, args...)); code != 0 || args[0] != "preview" {
+		return code
+	}
+
+	fmt.Printf("keep %s? [y/N] (reverts in %s) ", args[1], themePreviewTimeout)
+	answer := make(chan string, 1)
+	go func() {
+		var line string
+		fmt.Scanln(&line)
+		answer <- line
+	}()
+	select {
+	case line := <-answer:
+		if strings.EqualFold(strings.TrimSpace(line), "y") {
+			return runMsgCommand([]string{"theme", "confirm"})
+		}
+		return runMsgCommand([]string{"theme", "revert"})
+	case <-time.After(themePreviewTimeout):
+		fmt.Println("\nreverted")
+		return 0
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TimeTrackConfig.Show is "top" (today's most focused class, the default)
+// or "total". Time stops counting after IdleSeconds without activity.
+type TimeTrackConfig struct {
+	Show        string `json:"show"`
+	IdleSeconds int    `json:"idle_seconds"`
+}
+
+// timeTally accumulates today's seconds per key (a window class, a
+// workspace) and keeps one file per day under
+// $XDG_DATA_HOME/tui-bar/time/<kind>/.
+type timeTally struct {
+	kind     string
+	day      string
+	stats    map[string]float64
+	lastTick time.Time
+}
+
+func newTimeTally(kind string) timeTally {
+	day := time.Now().Format(time.DateOnly)
+	return timeTally{kind: kind, day: day, stats: loadTimeTally(kind, day)}
+}
+
+// tick adds the time since the last tick to key (nothing for an empty
+// key), rolling over to a new file at midnight.
+func (t *timeTally) tick(now time.Time, key string) tea.Cmd {
+	elapsed, counts := tickElapsed(&t.lastTick, now)
+
+	var cmd tea.Cmd
+	if day := now.Format(time.DateOnly); day != t.day {
+		cmd = t.save()
+		t.day, t.stats = day, make(map[string]float64)
+	}
+	if key != "" && counts {
+		t.stats[key] += elapsed.Seconds()
+	}
+	return cmd
+}
+
+func (t *timeTally) save() tea.Cmd {
+	kind, day, stats := t.kind, t.day, maps.Clone(t.stats)
+	return func() tea.Msg {
+		if err := saveTimeTally(kind, day, stats); err != nil {
+			log.Printf("%s time: %v", kind, err)
+		}
+		return nil
+	}
+}
+
+func (t *timeTally) get(key string) time.Duration {
+	return time.Duration(t.stats[key] * float64(time.Second))
+}
+
+// ranked lists today's keys, most time first.
+func (t *timeTally) ranked() []string {
+	keys := slices.Collect(maps.Keys(t.stats))
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(cmp.Compare(t.stats[b], t.stats[a]), cmp.Compare(a, b))
+	})
+	return keys
+}
+
+func (t *timeTally) total() time.Duration {
+	var seconds float64
+	for _, s := range t.stats {
+		seconds += s
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (t *timeTally) popup(width int) []segments {
+	total := t.total()
+	var header segments
+	header.add("", fmt.Sprintf(" today %s ", shortDuration(total)))
+	rows := []segments{header}
+
+	for _, key := range t.ranked() {
+		d := t.get(key)
+		var row segments
+		row.add("", fmt.Sprintf(" %s %6s %3.0f%% ", padRight(truncate(key, width), width), shortDuration(d), 100*d.Seconds()/max(total.Seconds(), 1)))
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (t *timeTally) export() any {
+	return map[string]any{"day": t.day, "seconds": t.stats}
+}
+
+func timeTallyPath(kind, day string) string {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "tui-bar", "time", kind, day+".json")
+}
+
+func loadTimeTally(kind, day string) map[string]float64 {
+	stats := make(map[string]float64)
+	if data, err := os.ReadFile(timeTallyPath(kind, day)); err == nil {
+		json.Unmarshal(data, &stats)
+	}
+	return stats
+}
+
+func saveTimeTally(kind, day string, stats map[string]float64) error {
+	path := timeTallyPath(kind, day)
+	if path == "" {
+		return fmt.Errorf("no data directory")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// shortDuration formats d as "42m" or "2h05".
+func shortDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Tooltips map module ids to format strings shown in a line reserved next
+// to the bar while the pointer is over the module, e.g.
+//   "cpu": "CPU {cpu}% · memory {memory}%"
+// Placeholders are the variables visibility rules see. The line is only
+// reserved when the terminal is tall enough.
+
+var tooltipPlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_.:]*)\}`)
+
+func (m model) wantsAllMotion() bool {
+	return m.hidden || m.config.Hide.AutoHideSeconds > 0 || len(m.config.Tooltips) > 0
+}
+
+// tooltipLine renders the reserved line, reporting false when there is
+// none: tooltips aren't configured or the bar fills the terminal.
+func (m model) tooltipLine(barHeight int) (string, bool) {
+	if len(m.config.Tooltips) == 0 || m.height <= barHeight {
+		return "", false
+	}
+	text := ""
+	if format, ok := m.config.Tooltips[m.hover]; ok {
+		vars := m.ruleVars()
+		text = tooltipPlaceholder.ReplaceAllStringFunc(format, func(match string) string {
+			return m.formatVar(vars[match[1:len(match)-1]])
+		})
+	}
+	return tooltipStyle.Width(m.width).MaxWidth(m.width).Render(truncate(text, m.width)), true
+}
+
+func (m model) formatVar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		if v == float64(int64(v)) {
+			return m.locale.int(int64(v))
+		}
+		return m.locale.float(v, 1)
+	}
+	return fmt.Sprint(v)
+}
+
+func hoverTarget(f frame, x, y int) string {
+	if !f.onBar(y) {
+		return ""
+	}
+	id, _ := splitTarget(zoneAt(f.zones, x))
+	return id
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// removable drives from UDisks2 on the system bus
+
+const (
+	udisksService    = "org.freedesktop.UDisks2"
+	udisksPath       = "/org/freedesktop/UDisks2"
+	udisksBlock      = udisksService + ".Block"
+	udisksFilesystem = udisksService + ".Filesystem"
+	udisksDrive      = udisksService + ".Drive"
+)
+
+type removableDrive struct {
+	path     dbus.ObjectPath // the drive
+	name     string
+	mounts   []string
+	blocks   []dbus.ObjectPath // mounted filesystems on it
+	powerOff bool
+}
+
+// id is the drive's name under /org/freedesktop/UDisks2/drives.
+func (d removableDrive) id() string {
+	return path.Base(string(d.path))
+}
+
+// watchUDisks signals on changed whenever a device or mount comes or
+// goes, until lifetime ends. Bursts collapse into one signal.
+func watchUDisks(lifetime context.Context) (<-chan struct{}, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range [][]dbus.MatchOption{
+		{dbus.WithMatchSender(udisksService), dbus.WithMatchInterface("org.freedesktop.DBus.ObjectManager")},
+		{dbus.WithMatchSender(udisksService), dbus.WithMatchInterface("org.freedesktop.DBus.Properties"), dbus.WithMatchMember("PropertiesChanged")},
+	} {
+		if err := conn.AddMatchSignal(match...); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	changed := make(chan struct{}, 1)
+	go func() {
+		for range signals {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	go func() {
+		<-lifetime.Done()
+		conn.Close()
+	}()
+	return changed, nil
+}
+
+// fetchRemovableDrives lists removable drives with at least one mounted
+// filesystem, leaving out anything UDisks marks as system or hidden.
+func fetchRemovableDrives() ([]removableDrive, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	if err := conn.Object(udisksService, udisksPath).Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).Store(&objects); err != nil {
+		return nil, err
+	}
+
+	byDrive := map[dbus.ObjectPath]*removableDrive{}
+	for objPath, ifaces := range objects {
+		block, fs := ifaces[udisksBlock], ifaces[udisksFilesystem]
+		if block == nil || fs == nil {
+			continue
+		}
+		if variantBool(block["HintSystem"]) || variantBool(block["HintIgnore"]) {
+			continue
+		}
+		mounts := mountPoints(fs["MountPoints"])
+		drivePath, _ := block["Drive"].Value().(dbus.ObjectPath)
+		drive := objects[drivePath][udisksDrive]
+		if len(mounts) == 0 || drive == nil || !variantBool(drive["Removable"]) && !variantBool(drive["MediaRemovable"]) {
+			continue
+		}
+
+		d, ok := byDrive[drivePath]
+		if !ok {
+			d = &removableDrive{path: drivePath, powerOff: variantBool(drive["CanPowerOff"])}
+			d.name, _ = block["IdLabel"].Value().(string)
+			if d.name == "" {
+				vendor, _ := drive["Vendor"].Value().(string)
+				model, _ := drive["Model"].Value().(string)
+				d.name = strings.TrimSpace(vendor + " " + model)
+			}
+			byDrive[drivePath] = d
+		}
+		d.mounts = append(d.mounts, mounts...)
+		d.blocks = append(d.blocks, objPath)
+	}
+
+	drives := make([]removableDrive, 0, len(byDrive))
+	for _, d := range byDrive {
+		slices.Sort(d.mounts)
+		drives = append(drives, *d)
+	}
+	slices.SortFunc(drives, func(a, b removableDrive) int {
+		return strings.Compare(string(a.path), string(b.path))
+	})
+	return drives, nil
+}
+
+func variantBool(v dbus.Variant) bool {
+	b, _ := v.Value().(bool)
+	return b
+}
+
+// mountPoints decodes the NUL-terminated byte strings UDisks uses.
+func mountPoints(v dbus.Variant) []string {
+	raw, _ := v.Value().([][]byte)
+	mounts := make([]string, 0, len(raw))
+	for _, m := range raw {
+		mounts = append(mounts, strings.TrimRight(string(m), "\x00"))
+	}
+	return mounts
+}
+
+// ejectDrive unmounts every filesystem on the drive and then powers it
+// off, or ejects the media when it can't be powered off.
+func ejectDrive(d removableDrive) error {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return err
+	}
+	options := map[string]dbus.Variant{}
+	for _, block := range d.blocks {
+		if err := conn.Object(udisksService, block).Call(udisksFilesystem+".Unmount", 0, options).Err; err != nil {
+			return err
+		}
+	}
+	method := udisksDrive + ".Eject"
+	if d.powerOff {
+		method = udisksDrive + ".PowerOff"
+	}
+	return conn.Object(udisksService, d.path).Call(method, 0, options).Err
+}
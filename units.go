@@ -0,0 +1,53 @@
+package main
+
+import "strings"
+
+// UnitsConfig picks how quantities are shown: "iec" (KiB, powers of 1024)
+// or "si" (kB, powers of 1000) for bytes, "c" or "f" for temperatures and
+// "kmh" or "mph" for speeds. Grouping puts the locale's thousands
+// separator into large numbers ("1,234 MB"); it's on by default.
+type UnitsConfig struct {
+	Bytes       string `json:"bytes"`
+	Temperature string `json:"temperature"`
+	Speed       string `json:"speed"`
+	Grouping    bool   `json:"grouping"`
+}
+
+type units struct {
+	config UnitsConfig
+	locale locale
+}
+
+func newUnits(config UnitsConfig, l locale) units {
+	return units{config: config, locale: l}
+}
+
+func (u units) bytes(n uint64) string {
+	base, suffixes := 1024.0, []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	if strings.EqualFold(u.config.Bytes, "si") {
+		base, suffixes = 1000.0, []string{"B", "kB", "MB", "GB", "TB"}
+	}
+	value, i := float64(n), 0
+	for value >= base && i < len(suffixes)-1 {
+		value /= base
+		i++
+	}
+	if i == 0 {
+		return u.locale.int(int64(n)) + suffixes[0]
+	}
+	return u.locale.float(value, 1) + suffixes[i]
+}
+
+func (u units) temperature(celsius float64) string {
+	if strings.EqualFold(u.config.Temperature, "f") {
+		return u.locale.float(celsius*9/5+32, 0) + "°F"
+	}
+	return u.locale.float(celsius, 0) + "°C"
+}
+
+func (u units) speed(kmh float64) string {
+	if strings.EqualFold(u.config.Speed, "mph") {
+		return u.locale.float(kmh/1.609344, 0) + " mph"
+	}
+	return u.locale.float(kmh, 0) + " km/h"
+}
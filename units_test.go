@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestUnits(t *testing.T) {
+	en := newLocale("en_US", true)
+	de := newLocale("de_DE", true)
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"iec bytes", newUnits(UnitsConfig{Bytes: "iec"}, en).bytes(1536), "1.5KiB"},
+		{"si bytes", newUnits(UnitsConfig{Bytes: "si"}, en).bytes(1500), "1.5kB"},
+		{"celsius", newUnits(UnitsConfig{Temperature: "c"}, en).temperature(21.4), "21°C"},
+		{"fahrenheit", newUnits(UnitsConfig{Temperature: "f"}, en).temperature(100), "212°F"},
+		{"default temperature", newUnits(UnitsConfig{}, en).temperature(-3), "-3°C"},
+		{"kmh", newUnits(UnitsConfig{Speed: "kmh"}, en).speed(100), "100 km/h"},
+		{"mph", newUnits(UnitsConfig{Speed: "mph"}, en).speed(100), "62 mph"},
+		{"grouped speed", newUnits(UnitsConfig{Speed: "kmh"}, de).speed(1234), "1.234 km/h"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -1,28 +1,12 @@
 package main
 
 import (
-	tea "github.com/charmbracelet/bubbletea"
 	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 type tickMsg time.Time
-type sysInfoMsg struct {
-	cpu  float64
-	mem  float64
-	disk float64
-}
-type batteryMsg struct {
-	level int
-	state string
-}
-type networkMsg struct {
-	name  string
-	state string
-}
-type hyprlandMsg struct {
-	activeWorkspace int
-	windowTitle     string
-}
 
 func tickCmd() tea.Cmd {
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
@@ -30,91 +14,151 @@ func tickCmd() tea.Cmd {
 	})
 }
 
-func getSystemInfo() tea.Cmd {
-	return func() tea.Msg {
-		cpu, mem, disk := fetchSystemStats()
-		return sysInfoMsg{
-			cpu:  cpu,
-			mem:  mem,
-			disk: disk,
-		}
-	}
-}
-
-func getBatteryInfo() tea.Cmd {
-	return func() tea.Msg {
-		level, state := fetchBatteryStats()
-		return batteryMsg{
-			level: level,
-			state: state,
-		}
-	}
-}
-
-func getNetworkInfo() tea.Cmd {
-	return func() tea.Msg {
-		name, state := fetchNetworkInfo()
-		return networkMsg{
-			name:  name,
-			state: state,
-		}
-	}
-}
-
-func getHyprlandInfo() tea.Cmd {
-	return func() tea.Msg {
-		ws := getActiveWorkspace()
-		win := getActiveWindow()
-		return hyprlandMsg{
-			activeWorkspace: ws,
-			windowTitle:     win,
-		}
-	}
-}
-
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.MouseMsg:
-		if msg.Type == tea.MouseLeft {
-			//TODO write mouse logic
+		m.lastActivity = time.Now()
+		if m.hidden {
+			return m.setHidden(false)
+		}
+
+		switch msg.Type {
+		case tea.MouseMotion:
+			if len(m.config.Tooltips) > 0 {
+				m.hover = hoverTarget(m.layoutFrame(), msg.X, msg.Y)
+			}
+		case tea.MouseLeft, tea.MouseRight, tea.MouseMiddle, tea.MouseWheelUp, tea.MouseWheelDown:
+			f := m.layoutFrame()
+			if target, ok := f.popupAt(msg.X, msg.Y); ok {
+				return m, m.dispatchMouse(m.popup, target, msg)
+			}
+			if !f.onBar(msg.Y) {
+				if msg.Type == tea.MouseLeft {
+					return m.setPopup("")
+				}
+				return m, nil
+			}
+			id, sub := splitTarget(zoneAt(f.zones, msg.X))
+			if cmd, ok := m.clickAction(id, msg); ok {
+				return m, cmd
+			}
+			return m, m.dispatchMouse(id, sub, msg)
 		}
 
 	case tea.KeyMsg:
+		if cmd, ok := m.popupKey(msg); ok {
+			return m, cmd
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
+			m.shutdown()
 			return m, tea.Quit
+		case "h":
+			return m.setHidden(!m.hidden)
+		case "d":
+			return m, m.toggleDebug()
+		case "f":
+			return m.setFocus(!m.focus)
+		case "esc":
+			return m.setPopup("")
 		}
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		return m, m.broadcast(msg)
+
+	case ipcMsg:
+		return m.handleIPC(msg)
+
+	case spinnerMsg:
+		if m.allReady() {
+			sdNotify("READY=1")
+			return m, nil
+		}
+		return m, spinnerCmd()
 
 	case tickMsg:
-		m.currTime = time.Time(msg)
+		var hideCmd tea.Cmd
+		m, hideCmd = m.checkAutoHide(time.Time(msg))
+		m = m.checkpointState()
+		m = m.pingWatchdog(time.Time(msg))
+		m = m.updateTheme(time.Time(msg))
+		var speechCmd tea.Cmd
+		m, speechCmd = m.speak(time.Time(msg))
 		return m, tea.Batch(
+			hideCmd,
+			speechCmd,
+			m.checkRecording(time.Time(msg)),
 			tickCmd(),
-			getSystemInfo(),
-			getBatteryInfo(),
-			getNetworkInfo(),
-			getHyprlandInfo(),
+			m.broadcast(msg),
 		)
 
-	case sysInfoMsg:
-		m.cpuUsage = msg.cpu
-		m.memUsage = msg.mem
-		m.diskUsage = msg.disk
+	case hyprEventBatchMsg:
+		cmds := make([]tea.Cmd, 0, len(msg.events)+1)
+		for _, event := range msg.events {
+			cmds = append(cmds, m.broadcast(event))
+		}
+		m.lastEvents = msg.at
+		m = m.observeScreencast(msg.events)
+		m = m.observeWorkspace(msg.events)
+		cmds = append(cmds, m.waitForHyprlandEvents())
+		return m, tea.Batch(cmds...)
+
+	case workspaceFocusMsg:
+		m.workspace = msg
+		return m, nil
 
-	case batteryMsg:
-		m.batLevel = msg.level
-		m.batState = msg.state
+	case recordingMsg:
+		m.recording = bool(msg)
+		return m, nil
 
-	case networkMsg:
-		m.netName = msg.name
-		m.netState = msg.state
+	case focusToggleMsg:
+		return m.setFocus(!m.focus)
 
-	case hyprlandMsg:
-		m.activeWorkspace = msg.activeWorkspace
-		m.windowTitle = msg.windowTitle
+	case popupToggleMsg:
+		if msg.id == m.popup {
+			return m.setPopup("")
+		}
+		return m.setPopup(msg.id)
+
+	case moduleMsg:
+		if mod, ok := m.moduleByID[msg.id]; ok && msg.msg != nil {
+			return m, m.faults.update(mod, msg.msg)
+		}
 	}
 	return m, nil
 }
+
+func (m model) toggleDebug() tea.Cmd {
+	if mod, ok := m.moduleByID["debug"]; ok {
+		return m.faults.update(mod, debugToggleMsg{})
+	}
+	return nil
+}
+
+func (m model) broadcast(msg tea.Msg) tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(m.modules))
+	for _, mod := range m.modules {
+		if m.paused(mod.Name(), msg) {
+			continue
+		}
+		cmds = append(cmds, m.faults.update(mod, msg))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m model) dispatchMouse(id, sub string, msg tea.MouseMsg) tea.Cmd {
+	mod, ok := m.moduleByID[id]
+	if !ok {
+		return nil
+	}
+	h, ok := mod.(mouseHandler)
+	if !ok {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.faults.guard(id, "mouse", func() { cmd = h.HandleMouse(sub, msg) })
+	return m.faults.guardCmd(id, cmd)
+}
@@ -1,114 +1,59 @@
 package main
 
-import (
-	"fmt"
-	"strings"
-	"time"
-
-	"github.com/charmbracelet/lipgloss"
-)
-
 func (m model) View() string {
-	if m.width == 0 {
-		return "Initializing.."
-	}
+	defer m.frames.measure()()
 
-	workspaces := renderWorkspaces(m.activeWorkspace, m.hypr)
-	clock := renderClock(m.currTime)
-	sysInfo := renderSystemInfo(m)
+	if m.hidden {
+		return m.anchor(m.renderHidden())
+	}
+	return m.layoutFrame().render()
+}
 
-	leftWidth := lipgloss.Width(workspaces)
-	centerWidth := lipgloss.Width(clock)
-	rightWidth := lipgloss.Width(sysInfo)
+func (m model) renderBar() (string, []hitZone) {
+	vars := m.ruleVars()
+	left := m.renderSection(m.sections[0], vars)
+	center := m.renderSection(m.sections[1], vars)
+	right := m.renderSection(m.sections[2], vars)
 
-	totalContentWidth := leftWidth + centerWidth + rightWidth
+	totalContentWidth := left.width + center.width + right.width
 	avaliableSpace := m.width - totalContentWidth
 
 	leftPadding := avaliableSpace / 3
 	rightPadding := avaliableSpace - leftPadding
 
-	statusbar := lipgloss.JoinHorizontal(
-		lipgloss.Top,
-		workspaces,
-		strings.Repeat(" ", leftPadding),
-		clock,
-		strings.Repeat(" ", rightPadding),
-		sysInfo,
-	)
+	var bar segments
+	bar.append(left)
+	bar.pad(leftPadding)
+	bar.append(center)
+	bar.pad(rightPadding)
+	bar.append(right)
 
-	return statusbar
+	return bar.renderAligned(barAlign(m.config.Bar.Align)), bar.zones
 }
 
-func renderWorkspaces(active int, hypr *HyprlandClient) string {
-	workspaces := []string{}
-
-	if hypr != nil {
-		wsList, err := hypr.GetWorkspaces()
-		if err == nil {
-			wsMap := make(map[int]bool)
-			for _, ws := range wsList {
-				wsMap[ws.ID] = true
-			}
-
-			for i := 1; i <= 10; i++ {
-				if !wsMap[1] {
-					continue
-				}
-				ws := fmt.Sprintf("%d", i)
-				if i == active {
-					workspaces = append(workspaces, workspaceActiveStyle.Render(ws))
-				} else {
-					workspaces = append(workspaces, workspaceStyle.Render(ws))
-				}
-			}
+func (m model) renderSection(ids []string, vars exprVars) segments {
+	var s segments
+	for _, id := range ids {
+		mod, ok := m.moduleByID[id]
+		if !ok || !m.moduleVisible(id, vars) {
+			continue
 		}
-	}
-	if len(workspaces) == 0 {
-		for i := 1; i <= 4; i++ {
-			ws := fmt.Sprintf("%d", i)
-			if i == active {
-				workspaces = append(workspaces, workspaceActiveStyle.Render(ws))
-			} else {
-				workspaces = append(workspaces, workspaceStyle.Render(ws))
-			}
+		var r segments
+		switch {
+		case m.dimmed(id):
+			r = m.renderDimmed(mod)
+		case m.stale(id):
+			r = m.renderStale(mod)
+		default:
+			r = m.faults.render(mod)
 		}
+		if r.width == 0 {
+			continue
+		}
+		if s.width > 0 {
+			s.pad(density.gap)
+		}
+		s.append(r)
 	}
-	return lipgloss.JoinHorizontal(lipgloss.Top, workspaces...)
-}
-
-func renderClock(t time.Time) string {
-	timeStr := t.Format("15:04;05 | Mon 02 Jan")
-	return clockStyle.Render(timeStr)
-}
-
-func renderSystemInfo(m model) string {
-	modules := []string{}
-
-	cpu := fmt.Sprintf("󰻠 %.1f%%", m.cpuUsage)
-	modules = append(modules, cpuStyle.Render(cpu))
-
-	memory := fmt.Sprintf("󰍛 %.1f%%", m.memUsage)
-	modules = append(modules, memoryStyle.Render(memory))
-
-	disk := fmt.Sprintf("󰋊 %.1f%%", m.diskUsage)
-	modules = append(modules, diskStyle.Render(disk))
-
-	netIcon := getNetworkIcon(m.netState)
-	network := fmt.Sprintf("%s %s", netIcon, m.netName)
-	modules = append(modules, networkStyle.Render(network))
-
-	batIcon := getBatteryIcon(m.batLevel, m.batState)
-	battery := fmt.Sprintf("%s %d%%", batIcon, m.batLevel)
-
-	var batStyle lipgloss.Style
-	if m.batState == "charging" {
-		batStyle = batteryChargingStyle
-	} else if m.batLevel < 20 {
-		batStyle = batteryLowStyle
-	} else {
-		batStyle = batteryStyle
-	}
-
-	modules = append(modules, batStyle.Render(battery))
-	return lipgloss.JoinHorizontal(lipgloss.Top, modules...)
+	return s
 }
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+type WallpaperConfig struct {
+	Directory    string   `json:"directory"`
+	Backend      string   `json:"backend"`
+	ThemeCommand []string `json:"theme_command"`
+}
+
+var wallpaperExtensions = []string{".jpg", ".jpeg", ".png", ".webp", ".gif", ".bmp"}
+
+// wallpaperBackend picks swww when its daemon answers, hyprpaper otherwise.
+func wallpaperBackend(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if exec.Command("swww", "query").Run() == nil {
+		return "swww"
+	}
+	return "hyprpaper"
+}
+
+func hyprpaperRequest(request string) (string, error) {
+	return hyprToolRequest("hyprpaper", request)
+}
+
+// hyprToolRequest talks to the IPC socket of a Hyprland companion tool
+// (hyprpaper, hyprsunset, ...), the same thing `hyprctl <tool>` does.
+func hyprToolRequest(tool, request string) (string, error) {
+	signature := hyprSignature()
+	if signature == "" {
+		return "", fmt.Errorf("not running in hyprland")
+	}
+	path := filepath.Join(hyprSocketDir(signature), "."+tool+".sock")
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return "", fmt.Errorf("%s is not running: %v", tool, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if _, err := conn.Write([]byte(request)); err != nil {
+		return "", err
+	}
+	buf := make([]byte, 8192)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf[:n])), nil
+}
+
+// currentWallpaper returns the image shown on the first monitor.
+func currentWallpaper(backend string) (string, error) {
+	switch backend {
+	case "swww":
+		out, err := exec.Command("swww", "query").Output()
+		if err != nil {
+			return "", err
+		}
+		// DP-1: 2560x1440, scale: 1, currently displaying: image: /path/to/img.png
+		for line := range strings.Lines(string(out)) {
+			if _, path, ok := strings.Cut(line, "image: "); ok {
+				return strings.TrimSpace(path), nil
+			}
+		}
+		return "", nil
+	case "hyprpaper":
+		out, err := hyprpaperRequest("listactive")
+		if err != nil {
+			return "", err
+		}
+		// DP-1 = /path/to/img.png
+		for line := range strings.Lines(out) {
+			if _, path, ok := strings.Cut(line, " = "); ok {
+				return strings.TrimSpace(path), nil
+			}
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown wallpaper backend %q", backend)
+}
+
+func setWallpaper(backend, path string) error {
+	switch backend {
+	case "swww":
+		return exec.Command("swww", "img", path).Run()
+	case "hyprpaper":
+		if _, err := hyprpaperRequest("preload " + path); err != nil {
+			return err
+		}
+		_, err := hyprpaperRequest("wallpaper ," + path)
+		return err
+	}
+	return fmt.Errorf("unknown wallpaper backend %q", backend)
+}
+
+func listWallpapers(dir string) []string {
+	entries, err := os.ReadDir(expandHome(dir))
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && slices.Contains(wallpaperExtensions, strings.ToLower(filepath.Ext(e.Name()))) {
+			files = append(files, filepath.Join(expandHome(dir), e.Name()))
+		}
+	}
+	return files
+}
+
+func nextWallpaper(files []string, current string, step int) string {
+	if len(files) == 0 {
+		return ""
+	}
+	i := slices.Index(files, current)
+	if i < 0 {
+		return files[0]
+	}
+	return files[(i+step+len(files))%len(files)]
+}
+
+// runThemeCommand regenerates the color scheme from the new wallpaper,
+// e.g. ["wal", "-n", "-i", "{}"] for pywal.
+func runThemeCommand(command []string, path string) error {
+	if len(command) == 0 {
+		return nil
+	}
+	args := make([]string, len(command))
+	for i, arg := range command {
+		args[i] = strings.ReplaceAll(arg, "{}", path)
+	}
+	return exec.Command(args[0], args[1:]...).Run()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WorkspaceModules maps a workspace (name or id) to modules shown or
+// hidden there, e.g. {"work": {"hide": ["ticker"]}, "music": {"show":
+// ["media"]}}. A module listed under any "show" appears only on those
+// workspaces. Visibility rules can also use workspace.id and
+// workspace.name.
+type WorkspaceModules struct {
+	Show []string `json:"show"`
+	Hide []string `json:"hide"`
+}
+
+type workspaceFocusMsg struct {
+	id   int
+	name string
+}
+
+func fetchWorkspaceFocus(hc *HyprlandClient) tea.Cmd {
+	if hc == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ws, err := hc.GetActiveWorkspace()
+		if err != nil {
+			return nil
+		}
+		return workspaceFocusMsg{id: ws.ID, name: ws.Name}
+	}
+}
+
+// observeWorkspace follows the focused workspace from workspace and
+// focusedmon events.
+func (m model) observeWorkspace(events []hyprEventMsg) model {
+	for _, e := range events {
+		switch {
+		case e.Type == "workspace":
+			if id, name, ok := workspaceFromEvent(HyprlandEvent(e)); ok {
+				m.workspace = workspaceFocusMsg{id: id, name: name}
+			}
+		case e.Type == "focusedmon" && len(e.Data) >= 2:
+			id, _ := strconv.Atoi(e.Data[1])
+			m.workspace = workspaceFocusMsg{id: id, name: e.Data[1]}
+		}
+	}
+	return m
+}
+
+func (w workspaceFocusMsg) is(key string) bool {
+	return key == w.name || key == strconv.Itoa(w.id)
+}
+
+// workspaceVisible applies the workspace_modules config to a module.
+func (m model) workspaceVisible(id string) bool {
+	rules := m.config.WorkspaceModules
+	if len(rules) == 0 {
+		return true
+	}
+	restricted, shown := false, false
+	for key, rule := range rules {
+		here := m.workspace.is(key)
+		if here && slices.Contains(rule.Hide, id) {
+			return false
+		}
+		if slices.Contains(rule.Show, id) {
+			restricted = true
+			shown = shown || here
+		}
+	}
+	return !restricted || shown
+}